@@ -1,19 +1,35 @@
 package main
 
 import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/debug"
 	"TODO-list/app/sdk/mux"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/migrate"
+	"TODO-list/business/sdk/sqldb"
+	"TODO-list/foundation/dotenv"
+	"TODO-list/foundation/keystore"
 	"TODO-list/foundation/logger"
 	"TODO-list/foundation/otel"
 	"context"
 	"database/sql"
+	"errors"
+	"expvar"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // the tz parameter of the task listings needs the zone database on any image
+
+	"github.com/ardanlabs/conf/v3"
 )
 
+// build is the git version of this program. It can be set at build time with
+// -ldflags "-X main.build=<version>".
+var build = "develop"
+
 func main() {
 	var log *logger.Logger
 	traceIDFn := func(ctx context.Context) string {
@@ -35,32 +51,206 @@ func main() {
 // run sets up the application, including database connection, server initialization,
 // and graceful shutdown logic.
 func run(ctx context.Context, log *logger.Logger) error {
+
+	// -------------------------------------------------------------------------
+	// Configuration
+
+	// Settings come from the defaults below, then the optional .env file, then
+	// TASKS_ prefixed environment variables and finally command line flags.
+	cfg := struct {
+		conf.Version
+		Web struct {
+			APIHost           string        `conf:"default:0.0.0.0:8080"`
+			DebugHost         string        `conf:"default:0.0.0.0:3010,help:address of the pprof/expvar/metrics server"`
+			CursorKey         string        `conf:"mask,help:secret signing the task listing cursors (empty uses a random key per process)"`
+			ReadTimeout       time.Duration `conf:"default:5s"`
+			WriteTimeout      time.Duration `conf:"default:10s"`
+			DebugWriteTimeout time.Duration `conf:"default:60s,help:write timeout of the debug server (CPU profiles take 30s)"`
+			IdleTimeout       time.Duration `conf:"default:120s"`
+			ShutdownTimeout   time.Duration `conf:"default:10s"`
+		}
+		DB struct {
+			Store           string        `conf:"default:mysql,help:persistence backend to use (mysql|sqlite|memory)"`
+			DSN             string        `conf:"mask,help:data source name (empty uses the local development database of the store)"`
+			Migrate         string        `conf:"default:check,help:schema migrations on startup (check refuses to start when pending|up applies them|off skips)"`
+			MaxOpenConns    int           `conf:"default:25"`
+			MaxIdleConns    int           `conf:"default:25"`
+			ConnMaxLifetime time.Duration `conf:"default:5m"`
+			ConnectTimeout  time.Duration `conf:"default:30s,help:how long to keep retrying the database at startup"`
+		}
+		Auth struct {
+			KeysFolder string        `conf:"default:zarf/keys/,help:folder of the RSA private keys named <kid>.pem"`
+			Issuer     string        `conf:"default:tasks,help:issuer claim the tokens must carry"`
+			ActiveKID  string        `conf:"help:key signing the tokens issued on login (empty uses the only key of the folder)"`
+			AccessTTL  time.Duration `conf:"default:15m,help:lifetime of the access tokens"`
+			RefreshTTL time.Duration `conf:"default:720h,help:lifetime of the refresh tokens"`
+		}
+		Trace struct {
+			Exporter    string  `conf:"default:none,help:where spans are sent (none|otlp|file)"`
+			Host        string  `conf:"default:localhost:4318,help:OTLP HTTP collector used by the otlp exporter"`
+			File        string  `conf:"default:traces.json,help:file the file exporter appends spans to"`
+			Probability float64 `conf:"default:0.05,help:fraction of new traces that are sampled"`
+		}
+	}{
+		Version: conf.Version{
+			Build: build,
+			Desc:  "Task service HTTP API",
+		},
+	}
+
+	if err := dotenv.Load(".env"); err != nil {
+		return fmt.Errorf("loading .env: %w", err)
+	}
+
+	const prefix = "TASKS"
+	help, err := conf.Parse(prefix, &cfg)
+	if err != nil {
+		if errors.Is(err, conf.ErrHelpWanted) {
+			fmt.Println(help)
+			return nil
+		}
+		return fmt.Errorf("parsing config: %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// App Starting
+
+	log.Info(ctx, "starting service", "version", cfg.Build)
+
+	out, err := conf.String(&cfg)
+	if err != nil {
+		return fmt.Errorf("generating config for output: %w", err)
+	}
+	log.Info(ctx, "startup", "config", out)
+
 	log.BuildInfo(ctx)
-	log.Info(ctx, "startup", "status", "initializing database support")
 
-	// Open a connection to the MySQL database using a DSN (Data Source Name).
-	// The `parseTime=true` parameter ensures that MySQL DATETIME and TIMESTAMP
-	// fields are automatically parsed into Go's time.Time type.
-	db, err := sql.Open("mysql", "root:root@tcp(localhost:3306)/todolist?parseTime=true")
+	// -------------------------------------------------------------------------
+	// Start Tracing Support
+
+	log.Info(ctx, "startup", "status", "initializing tracing support", "exporter", cfg.Trace.Exporter)
+
+	traceProvider, teardown, err := otel.InitTracing(otel.Config{
+		ServiceName: "TASKS",
+		Exporter:    cfg.Trace.Exporter,
+		Host:        cfg.Trace.Host,
+		File:        cfg.Trace.File,
+		Probability: cfg.Trace.Probability,
+	})
 	if err != nil {
-		return fmt.Errorf("connecting to database: %w", err)
+		return fmt.Errorf("starting tracing: %w", err)
 	}
-	// Ensure that the database connection is closed when the function exits.
-	defer db.Close()
+	defer teardown(context.Background())
 
-	// Create a channel to listen for interrupt signals (e.g., SIGINT, SIGTERM).
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+	tracer := traceProvider.Tracer("TASKS")
 
-	log.Info(ctx, "startup", "status", "initializing V1 API support")
+	// -------------------------------------------------------------------------
+	// Initialize authentication support
+
+	log.Info(ctx, "startup", "status", "initializing authentication support", "folder", cfg.Auth.KeysFolder)
+
+	ks := keystore.New()
+	n, err := ks.LoadRSAKeys(os.DirFS(cfg.Auth.KeysFolder))
+	if err != nil {
+		return fmt.Errorf("loading keys: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("no keys found in %s, create one with: admin genkey", cfg.Auth.KeysFolder)
+	}
+
+	kid := cfg.Auth.ActiveKID
+	if kid == "" {
+		if n > 1 {
+			return fmt.Errorf("%d keys found in %s, name the one signing new tokens with --auth-active-kid", n, cfg.Auth.KeysFolder)
+		}
+		kid = ks.KIDs()[0]
+	}
+	if _, err := ks.PrivateKey(kid); err != nil {
+		return fmt.Errorf("active kid[%s]: %w", kid, err)
+	}
+
+	ath, err := auth.New(auth.Config{
+		KeyLookup: ks,
+		Issuer:    cfg.Auth.Issuer,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
+
+	log.Info(ctx, "startup", "status", "initializing database support", "store", cfg.DB.Store)
 
 	// cfgMux defines the configuration for the mux-based web API, which includes
-	// the database connection.
+	// the selected persistence backend.
 	cfgMux := mux.Config{
-		DB:  db,
-		Log: log,
+		Build:      build,
+		Log:        log,
+		Tracer:     tracer,
+		Auth:       ath,
+		AuthKID:    kid,
+		AccessTTL:  cfg.Auth.AccessTTL,
+		RefreshTTL: cfg.Auth.RefreshTTL,
+		CursorKey:  []byte(cfg.Web.CursorKey),
+	}
+
+	// Cursors signed with a random key do not survive a restart and are not
+	// accepted by the other instances behind a load balancer.
+	if cfg.Web.CursorKey == "" {
+		log.Warn(ctx, "startup", "status", "no cursor key configured, using a random one")
 	}
 
+	if cfg.DB.Store == "memory" {
+		// The in-memory database lives as long as the process, which is handy
+		// for demos and integration tests that need no MySQL instance.
+		cfgMux.MemDB = memdb.New()
+	} else {
+		dialect, err := sqldb.ParseDialect(cfg.DB.Store)
+		if err != nil {
+			return fmt.Errorf("parsing store: %w", err)
+		}
+
+		// Open a connection to the database using a DSN (Data Source Name).
+		// The sqldb package takes care of the per dialect settings, such as
+		// parsing MySQL DATETIME fields into Go's time.Time type.
+		db, err := sqldb.Open(sqldb.Config{
+			Dialect:         dialect,
+			DSN:             cfg.DB.DSN,
+			MaxOpenConns:    cfg.DB.MaxOpenConns,
+			MaxIdleConns:    cfg.DB.MaxIdleConns,
+			ConnMaxLifetime: cfg.DB.ConnMaxLifetime,
+		})
+		if err != nil {
+			return fmt.Errorf("connecting to database: %w", err)
+		}
+		// Ensure that the database connection is closed when the function exits.
+		defer db.Close()
+
+		// sql.Open only validates its arguments, so keep pinging until the
+		// database answers. This covers the database container still booting
+		// when the service starts.
+		log.Info(ctx, "startup", "status", "waiting for database", "timeout", cfg.DB.ConnectTimeout.String())
+
+		connCtx, cancel := context.WithTimeout(ctx, cfg.DB.ConnectTimeout)
+		err = sqldb.StatusCheck(connCtx, db)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("waiting for database: %w", err)
+		}
+
+		if err := migrateSchema(ctx, log, db, dialect, cfg.DB.Migrate); err != nil {
+			return fmt.Errorf("migrating schema: %w", err)
+		}
+
+		sqldb.ExportStats(db)
+
+		cfgMux.DB = db
+	}
+
+	// Create a channel to listen for interrupt signals (e.g., SIGINT, SIGTERM).
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+
+	log.Info(ctx, "startup", "status", "initializing V1 API support")
+
 	// webAPI initializes a new WebAPI instance with the provided configuration.
 	webAPI, err := mux.WebAPI(cfgMux)
 	if err != nil {
@@ -69,19 +259,45 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	// Create an HTTP server with the API handler and specify the server address.
 	api := http.Server{
-		Addr:     "0.0.0.0:8080",
-		Handler:  webAPI,
-		ErrorLog: logger.NewStdLogger(log, logger.LevelError),
+		Addr:         cfg.Web.APIHost,
+		Handler:      webAPI,
+		ReadTimeout:  cfg.Web.ReadTimeout,
+		WriteTimeout: cfg.Web.WriteTimeout,
+		IdleTimeout:  cfg.Web.IdleTimeout,
+		ErrorLog:     logger.NewStdLogger(log, logger.LevelError),
 	}
 
-	// Create a channel to capture any errors from the HTTP server.
-	serverErrors := make(chan error, 1)
+	// Create a channel to capture any errors from the HTTP servers.
+	serverErrors := make(chan error, 2)
 	go func() {
 		log.Info(ctx, "startup", "status", "api router started", "host", api.Addr)
 		// Start the HTTP server and capture any errors.
 		serverErrors <- api.ListenAndServe()
 	}()
 
+	// -------------------------------------------------------------------------
+	// Start Debug Service
+
+	expvar.NewString("build").Set(build)
+
+	// The debug server serves pprof, expvar, the metrics and the log level
+	// handler on its own port so they are never exposed with the API.
+	debugServer := http.Server{
+		Addr:         cfg.Web.DebugHost,
+		Handler:      debug.Mux(log),
+		ReadTimeout:  cfg.Web.ReadTimeout,
+		WriteTimeout: cfg.Web.DebugWriteTimeout,
+		IdleTimeout:  cfg.Web.IdleTimeout,
+		ErrorLog:     logger.NewStdLogger(log, logger.LevelError),
+	}
+
+	go func() {
+		log.Info(ctx, "startup", "status", "debug router started", "host", debugServer.Addr)
+		if err := debugServer.ListenAndServe(); err != nil {
+			serverErrors <- fmt.Errorf("debug: %w", err)
+		}
+	}()
+
 	// -------------------------------------------------------------------------
 	// Graceful Shutdown
 
@@ -97,17 +313,49 @@ func run(ctx context.Context, log *logger.Logger) error {
 		defer log.Info(ctx, "shutdown", "status", "shutdown complete", "signal", sig)
 
 		// Create a context with a timeout to ensure the server shuts down gracefully.
-		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, cfg.Web.ShutdownTimeout)
 		defer cancel()
 
-		// Attempt to gracefully shut down the HTTP server.
-		if err := api.Shutdown(ctx); err != nil {
-			// If there is an error shutting down, forcibly close the server.
+		// Attempt to gracefully shut down the HTTP servers. Both are asked
+		// to stop before reporting any failure.
+		apiErr := api.Shutdown(ctx)
+		debugErr := debugServer.Shutdown(ctx)
+
+		if apiErr != nil || debugErr != nil {
+			// If there is an error shutting down, forcibly close the servers.
 			api.Close()
-			return fmt.Errorf("could not stop server gracefully: %w", err)
+			debugServer.Close()
+			return fmt.Errorf("could not stop server gracefully: %w", errors.Join(apiErr, debugErr))
 		}
 	}
 
 	// Return nil to indicate a successful shutdown.
 	return nil
 }
+
+// migrateSchema checks or applies the embedded schema migrations according to
+// the requested mode.
+func migrateSchema(ctx context.Context, log *logger.Logger, db *sql.DB, dialect sqldb.Dialect, mode string) error {
+	if mode == "off" {
+		return nil
+	}
+
+	migrator, err := migrate.New(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case "check":
+		return migrator.Check(ctx)
+
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Info(ctx, "startup", "status", "migration applied", "version", m.Version, "name", m.Name)
+		}
+		return err
+	}
+
+	return fmt.Errorf("unknown migrate mode %q", mode)
+}
@@ -0,0 +1,51 @@
+// Package commands contains the functionality for the set of commands
+// currently supported by the admin tool.
+package commands
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/projectbus/stores/projectdb"
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/taskbus/stores/taskdb"
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/tokenbus/stores/tokendb"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/userdb"
+	"database/sql"
+	"errors"
+	"io"
+	"text/tabwriter"
+)
+
+// ErrHelp provides context that help was given.
+var ErrHelp = errors.New("provided help")
+
+// Buses holds the business packages the commands operate through, so every
+// rule the API enforces applies to maintenance work as well.
+type Buses struct {
+	User    *userbus.Business
+	Project *projectbus.Business
+	Task    *taskbus.Business
+	Token   *tokenbus.Business
+}
+
+// NewBuses constructs the business packages on top of the database. The
+// tool never issues refresh tokens, it only revokes them, so the token
+// business gets no lifetime.
+func NewBuses(db *sql.DB) Buses {
+	userBus := userbus.NewBusiness(userdb.NewStore(db))
+	projectBus := projectbus.NewBusiness(projectdb.NewStore(db), userBus)
+	taskBus := taskbus.NewBusiness(taskdb.NewStore(db), userBus, projectBus)
+	tokenBus := tokenbus.NewBusiness(tokendb.NewStore(db), userBus, 0)
+
+	return Buses{
+		User:    userBus,
+		Project: projectBus,
+		Task:    taskBus,
+		Token:   tokenBus,
+	}
+}
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
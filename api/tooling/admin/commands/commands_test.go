@@ -0,0 +1,91 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"TODO-list/api/tooling/admin/commands"
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/sqldb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqldb.Open(sqldb.Config{Dialect: sqldb.SQLite, DSN: "file::memory:"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var out bytes.Buffer
+	assert.Error(t, commands.Health(ctx, &out, db, sqldb.SQLite), "pending migrations must fail the health check")
+	require.NoError(t, commands.Migrate(ctx, &out, db, sqldb.SQLite, []string{"up"}))
+	require.NoError(t, commands.Health(ctx, &out, db, sqldb.SQLite))
+
+	buses := commands.NewBuses(db)
+
+	out.Reset()
+	require.NoError(t, commands.Seed(ctx, &out, buses))
+	require.NoError(t, commands.Seed(ctx, &out, buses))
+	assert.Contains(t, out.String(), "seed data already loaded")
+
+	out.Reset()
+	require.NoError(t, commands.Users(ctx, &out, buses, []string{"create", "Bob", "bob@example.com"}))
+	assert.Equal(t, "user created: id[3] email[bob@example.com]\n", out.String())
+	refresh, _, err := buses.Token.Issue(ctx, 3)
+	require.NoError(t, err)
+	require.NoError(t, commands.Users(ctx, &out, buses, []string{"deactivate", "3"}))
+	_, _, err = buses.Token.Rotate(ctx, refresh)
+	assert.ErrorIs(t, err, tokenbus.ErrRevoked, "deactivating a user revokes their refresh tokens")
+	assert.Error(t, commands.Users(ctx, &out, buses, []string{"create", "Bad", "not-an-email"}))
+
+	usr, err := buses.User.QueryById(ctx, 3)
+	require.NoError(t, err)
+	assert.False(t, usr.Active)
+
+	admin, err := buses.User.Authenticate(ctx, "admin@example.com", "gophers123")
+	assert.NoError(t, err, "seeded users log in with the seed password")
+	assert.Equal(t, userbus.RoleAdmin, admin.Role)
+
+	require.NoError(t, commands.Users(ctx, &out, buses, []string{"role", "2", "ADMIN"}))
+	member, err := buses.User.QueryById(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, userbus.RoleAdmin, member.Role)
+	assert.Error(t, commands.Users(ctx, &out, buses, []string{"role", "2", "ROOT"}))
+
+	require.NoError(t, commands.Users(ctx, &out, buses, []string{"create", "Carol", "carol@example.com", "s3cret-pass"}))
+	_, err = buses.User.Authenticate(ctx, "carol@example.com", "s3cret-pass")
+	assert.NoError(t, err)
+
+	out.Reset()
+	require.NoError(t, commands.Projects(ctx, &out, buses, []string{"list"}))
+	assert.Contains(t, out.String(), "Getting Started")
+
+	require.NoError(t, commands.Tasks(ctx, &out, buses, []string{"finish", "1"}))
+	task, err := buses.Task.QueryByID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, task.FinishedAt.Valid)
+
+	out.Reset()
+	require.NoError(t, commands.Tasks(ctx, &out, buses, []string{"reopen", "1", "closed by mistake"}))
+	assert.Equal(t, "task reopened: id[1] reopenings[1]\n", out.String())
+
+	assert.ErrorIs(t, commands.Tasks(ctx, &out, buses, []string{"finish"}), commands.ErrHelp)
+
+	folder := t.TempDir()
+	out.Reset()
+	require.NoError(t, commands.GenKey(&out, folder))
+	assert.Contains(t, out.String(), "key created")
+
+	out.Reset()
+	require.NoError(t, commands.GenToken(ctx, &out, buses, folder, "tasks", []string{"1"}))
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "."), 3)
+
+	assert.ErrorIs(t, commands.GenToken(ctx, &out, buses, folder, "tasks", []string{"3"}), userbus.ErrUserInactive)
+	assert.Error(t, commands.GenToken(ctx, &out, buses, folder, "tasks", []string{"1", "unknown"}))
+}
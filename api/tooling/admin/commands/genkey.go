@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"TODO-list/foundation/keystore"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// GenKey creates an RSA private key in the keys folder. The file is named
+// after the new key id, which the service puts in the kid header of the
+// tokens it signs with it.
+func GenKey(w io.Writer, folder string) error {
+	privatePEM, err := keystore.GenerateRSAKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(folder, 0o700); err != nil {
+		return fmt.Errorf("creating keys folder: %w", err)
+	}
+
+	kid := uuid.NewString()
+	file := filepath.Join(folder, kid+".pem")
+
+	if err := os.WriteFile(file, []byte(privatePEM), 0o600); err != nil {
+		return fmt.Errorf("writing private key: %w", err)
+	}
+
+	fmt.Fprintf(w, "key created: kid[%s] file[%s]\n", kid, file)
+	return nil
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/foundation/keystore"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GenToken signs a token for an active user with a key of the keys folder.
+// The key id may be left out when the folder holds a single key.
+func GenToken(ctx context.Context, w io.Writer, buses Buses, folder string, issuer string, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return ErrHelp
+	}
+
+	userID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid user id %q: %w", args[0], err)
+	}
+
+	ks := keystore.New()
+	if _, err := ks.LoadRSAKeys(os.DirFS(folder)); err != nil {
+		return fmt.Errorf("loading keys: %w", err)
+	}
+
+	var kid string
+	switch {
+	case len(args) == 2:
+		kid = args[1]
+	case len(ks.KIDs()) == 1:
+		kid = ks.KIDs()[0]
+	default:
+		return fmt.Errorf("%d keys found in %s, name the kid to sign with", len(ks.KIDs()), folder)
+	}
+
+	usr, err := buses.User.QueryById(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("query user: %w", err)
+	}
+	if !usr.Active {
+		return fmt.Errorf("userID[%d]: %w", userID, userbus.ErrUserInactive)
+	}
+
+	ath, err := auth.New(auth.Config{
+		KeyLookup: ks,
+		Issuer:    issuer,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
+
+	token, err := ath.GenerateToken(kid, ath.NewClaims(usr, 8*time.Hour))
+	if err != nil {
+		if errors.Is(err, keystore.ErrKeyNotFound) {
+			return fmt.Errorf("kid[%s] not found in %s", kid, folder)
+		}
+		return fmt.Errorf("generating token: %w", err)
+	}
+
+	fmt.Fprintln(w, token)
+	return nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"TODO-list/business/sdk/migrate"
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Health pings the database and reports whether the schema is up to date.
+func Health(ctx context.Context, w io.Writer, db *sql.DB, dialect sqldb.Dialect) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	fmt.Fprintf(w, "database: ok (%s, %s)\n", dialect, time.Since(start).Round(time.Millisecond))
+
+	migrator, err := migrate.New(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	if err := migrator.Check(ctx); err != nil {
+		return fmt.Errorf("schema: %w", err)
+	}
+	fmt.Fprintln(w, "schema: up to date")
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"TODO-list/business/sdk/migrate"
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Migrate applies, reverts or reports the schema migrations.
+func Migrate(ctx context.Context, w io.Writer, db *sql.DB, dialect sqldb.Dialect, args []string) error {
+	if len(args) != 1 {
+		return ErrHelp
+	}
+
+	migrator, err := migrate.New(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(w, "applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(w, "schema is up to date")
+		}
+		return nil
+
+	case "down":
+		m, reverted, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if !reverted {
+			fmt.Fprintln(w, "no migration to revert")
+			return nil
+		}
+		fmt.Fprintf(w, "reverted %04d_%s\n", m.Version, m.Name)
+		return nil
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := newTable(w)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt.Valid {
+				appliedAt = s.AppliedAt.Time.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return tw.Flush()
+	}
+
+	return ErrHelp
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Projects lists the projects.
+func Projects(ctx context.Context, w io.Writer, buses Buses, args []string) error {
+	if len(args) != 1 || args[0] != "list" {
+		return ErrHelp
+	}
+
+	tw := newTable(w)
+	fmt.Fprintln(tw, "ID\tNAME\tACTIVE\tCREATED BY\tCREATED AT")
+
+	// Walk every page so the whole table is listed.
+	orderBy := []order.By{projectbus.DefaultOrderBy}
+	for n := 1; ; n++ {
+		pg := page.MustParse(strconv.Itoa(n), strconv.Itoa(page.MaxRows))
+
+		projects, err := buses.Project.Query(ctx, projectbus.QueryFilter{}, orderBy, pg)
+		if err != nil {
+			return fmt.Errorf("query projects: %w", err)
+		}
+
+		for _, prj := range projects {
+			fmt.Fprintf(tw, "%d\t%s\t%t\t%d\t%s\n", prj.ID, prj.Name, prj.Active, prj.CreatedBy, prj.CreatedAt.Format(time.RFC3339))
+		}
+
+		if len(projects) < pg.RowsPerPage() {
+			break
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/userbus"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Seed loads a small data set for local development. It does nothing when
+// the seed user already exists.
+func Seed(ctx context.Context, w io.Writer, buses Buses) error {
+	const (
+		email    = "admin@example.com"
+		password = "gophers123"
+	)
+
+	_, err := buses.User.QueryByEmail(ctx, email)
+	switch {
+	case err == nil:
+		fmt.Fprintln(w, "seed data already loaded")
+		return nil
+	case !errors.Is(err, userbus.ErrNotFound):
+		return fmt.Errorf("query seed user: %w", err)
+	}
+
+	admin, err := buses.User.Create(ctx, userbus.NewUser{Name: "Admin", Email: email, Password: password, Role: userbus.RoleAdmin})
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+
+	member, err := buses.User.Create(ctx, userbus.NewUser{Name: "Member", Email: "member@example.com", Password: password})
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+
+	prj, err := buses.Project.Create(ctx, projectbus.NewProject{Name: "Getting Started", CreatedBy: admin.ID})
+	if err != nil {
+		return fmt.Errorf("create project: %w", err)
+	}
+
+	if _, err := buses.Project.AddMember(ctx, prj.ID, projectbus.NewMember{UserID: member.ID, Role: projectbus.MemberRoleMember}); err != nil {
+		return fmt.Errorf("add project member: %w", err)
+	}
+
+	tasks := []taskbus.NewTask{
+		{
+			Title:       "Read the README",
+			Description: "Learn how to run the API and the admin tool.",
+			ProjectID:   prj.ID,
+			CreatedBy:   admin.ID,
+			AssignedTo:  sql.NullInt32{Int32: int32(member.ID), Valid: true},
+		},
+		{
+			Title:       "Create your first task",
+			Description: "Use POST /api/tasks to add a task to this project.",
+			ProjectID:   prj.ID,
+			CreatedBy:   admin.ID,
+		},
+	}
+
+	for _, nt := range tasks {
+		if _, err := buses.Task.Create(ctx, nt); err != nil {
+			return fmt.Errorf("create task: %w", err)
+		}
+	}
+
+	fmt.Fprintf(w, "seed data loaded: users[2] projects[1] tasks[%d]\n", len(tasks))
+	fmt.Fprintf(w, "log in as %s or member@example.com with password %s\n", email, password)
+	return nil
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Tasks finishes and reopens tasks.
+func Tasks(ctx context.Context, w io.Writer, buses Buses, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "finish":
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid task id %q: %w", args[1], err)
+		}
+
+		// The admin tool acts on behalf of no user.
+		if err := buses.Task.Finish(ctx, id, sql.NullInt32{}); err != nil {
+			return fmt.Errorf("finish task: %w", err)
+		}
+
+		fmt.Fprintf(w, "task finished: id[%d]\n", id)
+		return nil
+
+	case len(args) == 3 && args[0] == "reopen":
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid task id %q: %w", args[1], err)
+		}
+
+		task, err := buses.Task.Reopen(ctx, id, args[2], sql.NullInt32{})
+		if err != nil {
+			return fmt.Errorf("reopen task: %w", err)
+		}
+
+		fmt.Fprintf(w, "task reopened: id[%d] reopenings[%d]\n", id, task.ReopenCount)
+		return nil
+	}
+
+	return ErrHelp
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"TODO-list/business/domain/userbus"
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+)
+
+// Users creates users, sets their role or deactivates them.
+func Users(ctx context.Context, w io.Writer, buses Buses, args []string) error {
+	if len(args) == 0 {
+		return ErrHelp
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) != 3 && len(args) != 4 {
+			return ErrHelp
+		}
+
+		if _, err := mail.ParseAddress(args[2]); err != nil {
+			return fmt.Errorf("invalid email format: %w", err)
+		}
+
+		// Without a password the user can only get tokens from gentoken.
+		nu := userbus.NewUser{Name: args[1], Email: args[2]}
+		if len(args) == 4 {
+			nu.Password = args[3]
+		}
+
+		usr, err := buses.User.Create(ctx, nu)
+		if err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+
+		fmt.Fprintf(w, "user created: id[%d] email[%s]\n", usr.ID, usr.Email)
+		return nil
+
+	case "role":
+		if len(args) != 3 {
+			return ErrHelp
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid user id %q: %w", args[1], err)
+		}
+
+		role, err := userbus.ParseRole(args[2])
+		if err != nil {
+			return err
+		}
+
+		if err := buses.User.SetRole(ctx, id, role); err != nil {
+			return fmt.Errorf("set role: %w", err)
+		}
+
+		fmt.Fprintf(w, "user role set: id[%d] role[%s]\n", id, role)
+		return nil
+
+	case "deactivate":
+		if len(args) != 2 {
+			return ErrHelp
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid user id %q: %w", args[1], err)
+		}
+
+		if err := buses.User.Deactivate(ctx, id); err != nil {
+			return fmt.Errorf("deactivate user: %w", err)
+		}
+
+		if err := buses.Token.RevokeUser(ctx, id); err != nil {
+			return fmt.Errorf("revoke tokens: %w", err)
+		}
+
+		fmt.Fprintf(w, "user deactivated: id[%d]\n", id)
+		return nil
+	}
+
+	return ErrHelp
+}
@@ -0,0 +1,139 @@
+// This program performs administrative tasks for the task service.
+package main
+
+import (
+	"TODO-list/api/tooling/admin/commands"
+	"TODO-list/business/sdk/sqldb"
+	"TODO-list/foundation/dotenv"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+const usage = `Usage: admin [flags] <command> [args]
+
+Commands:
+  migrate up                    apply every pending schema migration
+  migrate down                  revert the most recently applied migration
+  migrate status                list the migrations and when they were applied
+  seed                          load sample users, a project and tasks
+  users create <name> <email> [password]
+                                create a user, who logs in with the password
+  users role <id> ADMIN|USER    set the role of a user
+  users deactivate <id>         deactivate a user and revoke their refresh tokens
+  projects list                 list the projects
+  tasks finish <id>             mark a task as finished
+  tasks reopen <id> <reason>    reopen a finished task
+  health                        ping the database and check the schema version
+  genkey                        create an RSA key in the keys folder
+  gentoken <user id> [kid]      sign an 8 hour token for an active user
+
+Flags:
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+
+	// The service settings are honored so both programs reach the same
+	// database by default.
+	if err := dotenv.Load(".env"); err != nil {
+		fmt.Fprintln(os.Stderr, "admin: loading .env:", err)
+		os.Exit(1)
+	}
+
+	store := flag.String("store", envOr("TASKS_DB_STORE", "mysql"), "database to administer: mysql or sqlite ($TASKS_DB_STORE)")
+	dsn := flag.String("dsn", os.Getenv("TASKS_DB_DSN"), "database data source name, defaults to the local development database of the store ($TASKS_DB_DSN)")
+	timeout := flag.Duration("timeout", time.Minute, "maximum time the command may run")
+	keysFolder := flag.String("keys", envOr("TASKS_AUTH_KEYS_FOLDER", "zarf/keys/"), "folder of the RSA private keys ($TASKS_AUTH_KEYS_FOLDER)")
+	issuer := flag.String("issuer", envOr("TASKS_AUTH_ISSUER", "tasks"), "issuer of the generated tokens ($TASKS_AUTH_ISSUER)")
+	flag.Parse()
+
+	cfg := config{
+		store:      *store,
+		dsn:        *dsn,
+		timeout:    *timeout,
+		keysFolder: *keysFolder,
+		issuer:     *issuer,
+	}
+
+	if err := run(cfg, flag.Args()); err != nil {
+		if errors.Is(err, commands.ErrHelp) {
+			flag.Usage()
+			os.Exit(2)
+		}
+
+		fmt.Fprintln(os.Stderr, "admin:", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key string, fallback string) string {
+	if v, exists := os.LookupEnv(key); exists {
+		return v
+	}
+	return fallback
+}
+
+// config holds the settings given by the flags.
+type config struct {
+	store      string
+	dsn        string
+	timeout    time.Duration
+	keysFolder string
+	issuer     string
+}
+
+func run(cfg config, args []string) error {
+	if len(args) == 0 {
+		return commands.ErrHelp
+	}
+
+	// Creating a key needs no database.
+	if args[0] == "genkey" {
+		return commands.GenKey(os.Stdout, cfg.keysFolder)
+	}
+
+	dialect, err := sqldb.ParseDialect(cfg.store)
+	if err != nil {
+		return fmt.Errorf("parsing store: %w", err)
+	}
+
+	db, err := sqldb.Open(sqldb.Config{
+		Dialect: dialect,
+		DSN:     cfg.dsn,
+	})
+	if err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	buses := commands.NewBuses(db)
+
+	switch args[0] {
+	case "migrate":
+		return commands.Migrate(ctx, os.Stdout, db, dialect, args[1:])
+	case "seed":
+		return commands.Seed(ctx, os.Stdout, buses)
+	case "users":
+		return commands.Users(ctx, os.Stdout, buses, args[1:])
+	case "projects":
+		return commands.Projects(ctx, os.Stdout, buses, args[1:])
+	case "tasks":
+		return commands.Tasks(ctx, os.Stdout, buses, args[1:])
+	case "health":
+		return commands.Health(ctx, os.Stdout, db, dialect)
+	case "gentoken":
+		return commands.GenToken(ctx, os.Stdout, buses, cfg.keysFolder, cfg.issuer, args[1:])
+	}
+
+	return commands.ErrHelp
+}
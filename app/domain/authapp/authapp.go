@@ -0,0 +1,129 @@
+// Package authapp maintains the app layer api for logging in and for the
+// refresh tokens.
+package authapp
+
+import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/foundation/web"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// App handles the application layer of authentication.
+type App struct {
+	userBus   *userbus.Business
+	tokenBus  *tokenbus.Business
+	auth      *auth.Auth
+	kid       string
+	accessTTL time.Duration
+}
+
+// newApp creates a new instance of App issuing access tokens signed with the
+// kid key that expire after accessTTL.
+func newApp(userBus *userbus.Business, tokenBus *tokenbus.Business, ath *auth.Auth, kid string, accessTTL time.Duration) *App {
+	return &App{
+		userBus:   userBus,
+		tokenBus:  tokenBus,
+		auth:      ath,
+		kid:       kid,
+		accessTTL: accessTTL,
+	}
+}
+
+// Token logs a user in with the email and password, sent with basic auth or
+// as a JSON document, and returns an access token and a refresh token.
+func (a *App) Token(ctx context.Context, r *http.Request) web.Encoder {
+	var cred Credentials
+	if email, password, ok := r.BasicAuth(); ok {
+		cred = Credentials{Email: email, Password: password}
+	} else if err := web.Decode(r, &cred); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userBus.Authenticate(ctx, cred.Email, cred.Password)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	refresh, _, err := a.tokenBus.Issue(ctx, usr.ID)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return a.newToken(usr, refresh)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. The refresh token sent can not be used again.
+func (a *App) Refresh(ctx context.Context, r *http.Request) web.Encoder {
+	var rt RefreshToken
+	if err := web.Decode(r, &rt); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	refresh, busToken, err := a.tokenBus.Rotate(ctx, rt.RefreshToken)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	// The roles are read again so a changed role applies from the next
+	// access token on.
+	usr, err := a.userBus.QueryById(ctx, busToken.UserID)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return a.newToken(usr, refresh)
+}
+
+// Revoke revokes a refresh token, logging the user out once the access
+// token expires.
+func (a *App) Revoke(ctx context.Context, r *http.Request) web.Encoder {
+	var rt RefreshToken
+	if err := web.Decode(r, &rt); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.tokenBus.Revoke(ctx, rt.RefreshToken); err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return nil
+}
+
+// newToken signs an access token for the user and pairs it with the refresh
+// token.
+func (a *App) newToken(usr userbus.User, refresh string) web.Encoder {
+	access, err := a.auth.GenerateToken(a.kid, a.auth.NewClaims(usr, a.accessTTL))
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return Token{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(a.accessTTL.Seconds()),
+		RefreshToken: refresh,
+	}
+}
+
+// errCode maps the business errors to the code returned to the caller.
+func errCode(err error) errs.ErrCode {
+	switch {
+	case errors.Is(err, userbus.ErrAuthFailure),
+		errors.Is(err, userbus.ErrNotFound),
+		errors.Is(err, tokenbus.ErrNotFound),
+		errors.Is(err, tokenbus.ErrExpired),
+		errors.Is(err, tokenbus.ErrRevoked):
+		return errs.Unauthenticated
+	case errors.Is(err, userbus.ErrUserInactive):
+		return errs.PermissionDenied
+	}
+
+	return errs.InternalOnlyLog
+}
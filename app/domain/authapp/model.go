@@ -0,0 +1,52 @@
+package authapp
+
+import (
+	"TODO-list/app/sdk/errs"
+	"encoding/json"
+)
+
+// Credentials represents the email and password of a user logging in.
+type Credentials struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Decode decodes a JSON byte slice into a Credentials struct.
+func (c *Credentials) Decode(data []byte) error {
+	return json.Unmarshal(data, &c)
+}
+
+// Validate checks the fields of the credentials.
+func (c Credentials) Validate() error {
+	return errs.Check(c)
+}
+
+// RefreshToken represents the refresh token sent to rotate or revoke it.
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Decode decodes a JSON byte slice into a RefreshToken struct.
+func (rt *RefreshToken) Decode(data []byte) error {
+	return json.Unmarshal(data, &rt)
+}
+
+// Validate checks the fields of the refresh token.
+func (rt RefreshToken) Validate() error {
+	return errs.Check(rt)
+}
+
+// Token represents the tokens returned to a user that logged in. ExpiresIn
+// is the lifetime of the access token in seconds.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Encode encodes the Token struct into a JSON byte slice.
+func (t Token) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(t)
+	return data, "application/json", err
+}
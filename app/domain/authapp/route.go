@@ -0,0 +1,36 @@
+package authapp
+
+import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/foundation/logger"
+	"TODO-list/foundation/web"
+	"net/http"
+	"time"
+)
+
+// Config contains the dependencies required for initializing the auth application.
+type Config struct {
+	UserBus  *userbus.Business
+	TokenBus *tokenbus.Business
+	Logger   *logger.Logger
+	Auth     *auth.Auth
+
+	// KID names the key signing the access tokens.
+	KID string
+
+	// AccessTTL is how long an access token is accepted.
+	AccessTTL time.Duration
+}
+
+// Routes sets up the HTTP routes for the token endpoints. They are open to
+// anonymous callers, who prove who they are with a password or a refresh
+// token instead.
+func Routes(app *web.App, cfg Config) {
+	appAuth := newApp(cfg.UserBus, cfg.TokenBus, cfg.Auth, cfg.KID, cfg.AccessTTL)
+
+	app.HandlerFunc(http.MethodPost, "", "/api/auth/token", appAuth.Token, nil)
+	app.HandlerFunc(http.MethodPost, "", "/api/auth/refresh", appAuth.Refresh, nil)
+	app.HandlerFunc(http.MethodPost, "", "/api/auth/revoke", appAuth.Revoke, nil)
+}
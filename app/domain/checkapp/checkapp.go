@@ -0,0 +1,86 @@
+// Package checkapp maintains the app layer api for the check domain.
+package checkapp
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/sdk/sqldb"
+	"TODO-list/foundation/logger"
+	"TODO-list/foundation/web"
+	"context"
+	"database/sql"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// App manages the set of app layer api functions for the check domain.
+type App struct {
+	build string
+	log   *logger.Logger
+	db    *sql.DB
+}
+
+// newApp constructs a check app API for use. The db may be nil when the
+// service runs on the in-memory store.
+func newApp(build string, log *logger.Logger, db *sql.DB) *App {
+	return &App{
+		build: build,
+		log:   log,
+		db:    db,
+	}
+}
+
+// Readiness checks if the database is ready and if not will return a 503
+// status. Do not respond by just returning an error because further up in
+// the call stack it will interpret that as a non-trusted error.
+func (a *App) Readiness(ctx context.Context, r *http.Request) web.Encoder {
+	if a.db == nil {
+		return status{Status: "ok"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if err := sqldb.StatusCheck(ctx, a.db); err != nil {
+		a.log.Info(ctx, "readiness failure", "ERROR", err)
+		return errs.Newf(errs.Unavailable, "database not ready")
+	}
+
+	return status{Status: "ok"}
+}
+
+// Liveness returns simple status info if the service is alive. The build
+// details are the same ones logger.BuildInfo writes at startup.
+func (a *App) Liveness(ctx context.Context, r *http.Request) web.Encoder {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unavailable"
+	}
+
+	info := Info{
+		Status:       "up",
+		Build:        a.build,
+		Host:         host,
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		NumGoroutine: runtime.NumGoroutine(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.VCSRevision = s.Value
+			case "vcs.time":
+				info.VCSTime = s.Value
+			case "vcs.modified":
+				info.VCSModified = s.Value == "true"
+			}
+		}
+	}
+
+	return info
+}
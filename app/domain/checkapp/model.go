@@ -0,0 +1,33 @@
+package checkapp
+
+import "encoding/json"
+
+// Info represents information about the service.
+type Info struct {
+	Status       string `json:"status,omitempty"`
+	Build        string `json:"build,omitempty"`
+	GoVersion    string `json:"goversion,omitempty"`
+	VCSRevision  string `json:"vcs_revision,omitempty"`
+	VCSTime      string `json:"vcs_time,omitempty"`
+	VCSModified  bool   `json:"vcs_modified,omitempty"`
+	Host         string `json:"host,omitempty"`
+	GOMAXPROCS   int    `json:"GOMAXPROCS,omitempty"`
+	NumGoroutine int    `json:"goroutines,omitempty"`
+}
+
+// Encode implements the encoder interface.
+func (app Info) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// status represents the result of the readiness check.
+type status struct {
+	Status string `json:"status"`
+}
+
+// Encode implements the encoder interface.
+func (app status) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
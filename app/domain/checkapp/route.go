@@ -0,0 +1,24 @@
+package checkapp
+
+import (
+	"TODO-list/foundation/logger"
+	"TODO-list/foundation/web"
+	"database/sql"
+	"net/http"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build  string
+	Logger *logger.Logger
+	DB     *sql.DB
+}
+
+// Routes adds specific routes for this group. The checks skip the
+// application middleware so orchestrator probes stay cheap.
+func Routes(app *web.App, cfg Config) {
+	api := newApp(cfg.Build, cfg.Logger, cfg.DB)
+
+	app.HandlerFuncNoMid(http.MethodGet, "", "/readiness", api.Readiness)
+	app.HandlerFuncNoMid(http.MethodGet, "", "/liveness", api.Liveness)
+}
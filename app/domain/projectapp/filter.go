@@ -0,0 +1,66 @@
+package projectapp
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/domain/projectbus"
+	"net/http"
+	"strconv"
+)
+
+type queryParams struct {
+	Page      string
+	Rows      string
+	OrderBy   string
+	Active    string
+	CreatedBy string
+	Name      string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:      values.Get("page"),
+		Rows:      values.Get("rows"),
+		OrderBy:   values.Get("orderBy"),
+		Active:    values.Get("active"),
+		CreatedBy: values.Get("created_by"),
+		Name:      values.Get("name"),
+	}
+
+	return filter
+}
+
+// parseFilter converts the query string into a filter.
+func parseFilter(qp queryParams) (projectbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter projectbus.QueryFilter
+
+	if qp.Active != "" {
+		active, err := strconv.ParseBool(qp.Active)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError("active", err)...)
+		} else {
+			filter.Active = &active
+		}
+	}
+
+	if qp.CreatedBy != "" {
+		createdBy, err := strconv.Atoi(qp.CreatedBy)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError("created_by", err)...)
+		} else {
+			filter.CreatedBy = &createdBy
+		}
+	}
+
+	if qp.Name != "" {
+		filter.Name = &qp.Name
+	}
+
+	if fieldErrors != nil {
+		return projectbus.QueryFilter{}, fieldErrors
+	}
+
+	return filter, nil
+}
@@ -1,6 +1,7 @@
 package projectapp
 
 import (
+	"TODO-list/app/sdk/errs"
 	"TODO-list/business/domain/projectbus"
 	"encoding/json"
 	"time"
@@ -8,8 +9,7 @@ import (
 
 // NewProject represents the input data required to create a new project.
 type NewProject struct {
-	Name      string `json:"name"`
-	CreatedBy int    `json:"created_by"`
+	Name string `json:"name"`
 }
 
 // Decode decodes a JSON byte slice into a NewProject struct.
@@ -18,10 +18,11 @@ func (np *NewProject) Decode(data []byte) error {
 }
 
 // toBusNewProject converts a NewProject struct from the application layer to the business layer representation.
-func toBusNewProject(np NewProject) projectbus.NewProject {
+// The creator is the authenticated user.
+func toBusNewProject(np NewProject, createdBy int) projectbus.NewProject {
 	return projectbus.NewProject{
 		Name:      np.Name,
-		CreatedBy: np.CreatedBy,
+		CreatedBy: createdBy,
 	}
 }
 
@@ -85,3 +86,74 @@ func toBusUpdateProject(up UpdateProject) projectbus.UpdateProject {
 		Name: up.Name,
 	}
 }
+
+// NewMember represents the input data required to add a member to a project.
+type NewMember struct {
+	UserID int    `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required"`
+}
+
+// Decode decodes a JSON byte slice into a NewMember struct.
+func (nm *NewMember) Decode(data []byte) error {
+	return json.Unmarshal(data, &nm)
+}
+
+// Validate checks the fields of the new member.
+func (nm NewMember) Validate() error {
+	return errs.Check(nm)
+}
+
+// toBusNewMember converts a NewMember struct from the application layer to the business layer representation.
+func toBusNewMember(nm NewMember) (projectbus.NewMember, error) {
+	role, err := projectbus.ParseMemberRole(nm.Role)
+	if err != nil {
+		return projectbus.NewMember{}, errs.NewFieldsError("role", err)
+	}
+
+	return projectbus.NewMember{
+		UserID: nm.UserID,
+		Role:   role,
+	}, nil
+}
+
+// Member represents a project member in the application layer.
+type Member struct {
+	ProjectID int       `json:"project_id"`
+	UserID    int       `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Encode encodes the Member struct into a JSON byte slice.
+func (m Member) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(m)
+	return data, "application/json", err
+}
+
+// toAppMember converts a Member struct from the business layer to the application layer representation.
+func toAppMember(mbr projectbus.Member) Member {
+	return Member{
+		ProjectID: mbr.ProjectID,
+		UserID:    mbr.UserID,
+		Role:      mbr.Role.String(),
+		CreatedAt: mbr.CreatedAt,
+	}
+}
+
+// Members represents a collection of Member entities.
+type Members []Member
+
+// Encode encodes the Members slice into a JSON byte slice.
+func (ms Members) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ms)
+	return data, "application/json", err
+}
+
+// toAppMembers converts a slice of Member structs from the business layer to the application layer representation.
+func toAppMembers(members []projectbus.Member) Members {
+	app := make(Members, len(members))
+	for i, mbr := range members {
+		app[i] = toAppMember(mbr)
+	}
+	return app
+}
@@ -0,0 +1,12 @@
+package projectapp
+
+import (
+	"TODO-list/business/domain/projectbus"
+)
+
+var orderByFields = map[string]string{
+	"id":         projectbus.OrderByID,
+	"name":       projectbus.OrderByName,
+	"created_at": projectbus.OrderByCreatedAt,
+	"created_by": projectbus.OrderByCreatedBy,
+}
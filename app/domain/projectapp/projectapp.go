@@ -1,11 +1,17 @@
 package projectapp
 
 import (
+	"TODO-list/app/sdk/auth"
 	"TODO-list/app/sdk/errs"
+	"TODO-list/app/sdk/mid"
+	"TODO-list/app/sdk/query"
 	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 	"TODO-list/foundation/web"
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 )
@@ -13,11 +19,16 @@ import (
 // App handles the application layer for project-related operations.
 type App struct {
 	projectBus *projectbus.Business
+	auth       *auth.Auth
 }
 
-// newApp creates a new instance of App with the provided business layer (projectBus).
-func newApp(projectBus *projectbus.Business) *App {
-	return &App{projectBus: projectBus}
+// newApp creates a new instance of App with the provided business layer
+// (projectBus) and the auth checking who may hand out the owner role.
+func newApp(projectBus *projectbus.Business, ath *auth.Auth) *App {
+	return &App{
+		projectBus: projectBus,
+		auth:       ath,
+	}
 }
 
 // Create handles the creation of a new project.
@@ -27,25 +38,49 @@ func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	if app.CreatedBy == 0 {
-		return errs.New(errs.InvalidArgument, fmt.Errorf("created_by is required"))
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
 	}
 
-	projectBus, err := a.projectBus.Create(ctx, toBusNewProject(app))
+	projectBus, err := a.projectBus.Create(ctx, toBusNewProject(app, userID))
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return toAppProject(projectBus)
 }
 
-// Query retrieves a list of all projects from the business layer.
+// Query retrieves a page of the projects matching the query string.
 func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
-	projectsBus, err := a.projectBus.Query(ctx)
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldsError("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, projectbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldsError("orderBy", err)
+	}
+
+	projectsBus, err := a.projectBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	total, err := a.projectBus.Count(ctx, filter)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
-	return toAppProjects(projectsBus)
+
+	return query.NewResult(toAppProjects(projectsBus), total, page)
 }
 
 // QueryByID retrieves a specific project by its ID from the business layer.
@@ -57,7 +92,7 @@ func (a *App) QueryByID(ctx context.Context, r *http.Request) web.Encoder {
 
 	projectBus, err := a.projectBus.QueryById(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return toAppProject(projectBus)
@@ -77,7 +112,7 @@ func (a *App) Update(ctx context.Context, r *http.Request) web.Encoder {
 
 	err = a.projectBus.Update(ctx, id, toBusUpdateProject(up))
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
@@ -92,7 +127,7 @@ func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
 
 	err = a.projectBus.Delete(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
@@ -107,8 +142,124 @@ func (a *App) Deactivate(ctx context.Context, r *http.Request) web.Encoder {
 
 	err = a.projectBus.Deactivate(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
+	}
+
+	return nil
+}
+
+// QueryMembers lists the members of a project.
+func (a *App) QueryMembers(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	members, err := a.projectBus.QueryMembers(ctx, id)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppMembers(members)
+}
+
+// AddMember adds a user to a project with the given role.
+func (a *App) AddMember(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	var app NewMember
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nm, err := toBusNewMember(app)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	if nm.Role == projectbus.MemberRoleOwner {
+		if err := a.authorizeOwners(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	mbr, err := a.projectBus.AddMember(ctx, id, nm)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppMember(mbr)
+}
+
+// RemoveMember removes a user from a project.
+func (a *App) RemoveMember(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := strconv.Atoi(web.Param(r, "user_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	mbr, err := a.projectBus.QueryMember(ctx, id, userID)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	if mbr.Role == projectbus.MemberRoleOwner {
+		if err := a.authorizeOwners(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := a.projectBus.RemoveMember(ctx, id, userID); err != nil {
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
 }
+
+// authorizeOwners lets only admins and the owners of the project grant or
+// take away the owner role, so a maintainer can not hand a project over.
+func (a *App) authorizeOwners(ctx context.Context, projectID int) *errs.Error {
+	members, err := a.projectBus.QueryMembers(ctx, projectID)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	var owners []int
+	for _, mbr := range members {
+		if mbr.Role == projectbus.MemberRoleOwner {
+			owners = append(owners, mbr.UserID)
+		}
+	}
+
+	if err := a.auth.Authorize(mid.GetClaims(ctx), auth.RuleAdminOrSubject, owners...); err != nil {
+		return errs.New(errs.PermissionDenied, err)
+	}
+
+	return nil
+}
+
+// errCode maps the business errors to the code returned to the caller.
+func errCode(err error) errs.ErrCode {
+	switch {
+	case errors.Is(err, projectbus.ErrNotFound),
+		errors.Is(err, projectbus.ErrMemberNotFound),
+		errors.Is(err, userbus.ErrNotFound):
+		return errs.NotFound
+	case errors.Is(err, projectbus.ErrMemberExists):
+		return errs.AlreadyExists
+	case errors.Is(err, projectbus.ErrProjectInactive),
+		errors.Is(err, projectbus.ErrLastOwner),
+		errors.Is(err, userbus.ErrUserInactive):
+		return errs.FailedPrecondition
+	}
+
+	return errs.InternalOnlyLog
+}
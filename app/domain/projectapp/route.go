@@ -1,7 +1,10 @@
 package projectapp
 
 import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/mid"
 	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/userbus"
 	"TODO-list/foundation/logger"
 	"TODO-list/foundation/web"
 	"net/http"
@@ -9,19 +12,33 @@ import (
 
 // Config contains the dependencies required for initializing the project application.
 type Config struct {
+	UserBus    *userbus.Business
 	ProjectBus *projectbus.Business
 	Logger     *logger.Logger
+	Auth       *auth.Auth
 }
 
 // Routes sets up the HTTP routes for the project-related API endpoints.
 func Routes(web *web.App, cfg Config) {
-	app := newApp(cfg.ProjectBus)
+	authen := mid.Authenticate(cfg.Auth, cfg.UserBus)
 
-	web.HandlerFunc(http.MethodPost, "", "/api/project", app.Create, nil)
-	web.HandlerFunc(http.MethodGet, "", "/api/project", app.Query, nil)
-	web.HandlerFunc(http.MethodGet, "", "/api/project/{id}", app.QueryByID, nil)
-	web.HandlerFunc(http.MethodPut, "", "/api/project/{id}", app.Update, nil)
-	web.HandlerFunc(http.MethodDelete, "", "/api/project/{id}", app.Delete, nil)
-	web.HandlerFunc(http.MethodDelete, "", "/api/project/{id}/deactivate", app.Deactivate, nil)
+	app := newApp(cfg.ProjectBus, cfg.Auth)
 
+	ruleAny := mid.Authorize(cfg.Auth, auth.RuleAny)
+	ruleEditors := mid.AuthorizeProject(cfg.Auth, cfg.ProjectBus, auth.RuleAdminOrSubject,
+		projectbus.MemberRoleOwner, projectbus.MemberRoleMaintainer, projectbus.MemberRoleMember)
+	ruleManagers := mid.AuthorizeProject(cfg.Auth, cfg.ProjectBus, auth.RuleAdminOrSubject,
+		projectbus.MemberRoleOwner, projectbus.MemberRoleMaintainer)
+	ruleOwners := mid.AuthorizeProject(cfg.Auth, cfg.ProjectBus, auth.RuleAdminOrSubject,
+		projectbus.MemberRoleOwner)
+
+	web.HandlerFunc(http.MethodPost, "", "/api/project", app.Create, authen, ruleAny)
+	web.HandlerFunc(http.MethodGet, "", "/api/project", app.Query, authen, ruleAny)
+	web.HandlerFunc(http.MethodGet, "", "/api/project/{id}", app.QueryByID, authen, ruleAny)
+	web.HandlerFunc(http.MethodPut, "", "/api/project/{id}", app.Update, authen, ruleEditors)
+	web.HandlerFunc(http.MethodDelete, "", "/api/project/{id}", app.Delete, authen, ruleOwners)
+	web.HandlerFunc(http.MethodDelete, "", "/api/project/{id}/deactivate", app.Deactivate, authen, ruleOwners)
+	web.HandlerFunc(http.MethodGet, "", "/api/project/{id}/members", app.QueryMembers, authen, ruleAny)
+	web.HandlerFunc(http.MethodPost, "", "/api/project/{id}/members", app.AddMember, authen, ruleManagers)
+	web.HandlerFunc(http.MethodDelete, "", "/api/project/{id}/members/{user_id}", app.RemoveMember, authen, ruleManagers)
 }
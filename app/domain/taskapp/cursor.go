@@ -0,0 +1,20 @@
+package taskapp
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"time"
+)
+
+// cursorToken is the position signed into the next_cursor of the keyset
+// listing.
+type cursorToken struct {
+	CreatedAt time.Time `json:"c"`
+	ID        int       `json:"i"`
+}
+
+func toCursorToken(c taskbus.Cursor) cursorToken {
+	return cursorToken{
+		CreatedAt: c.CreatedAt,
+		ID:        c.ID,
+	}
+}
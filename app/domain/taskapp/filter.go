@@ -0,0 +1,192 @@
+package taskapp
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/domain/taskbus"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type queryParams struct {
+	Cursor            string
+	CursorMode        bool
+	Page              string
+	Rows              string
+	OrderBy           string
+	ProjectID         string
+	AssignedTo        string
+	CreatedBy         string
+	ParentID          string
+	Finished          string
+	Status            string
+	Open              string
+	Priority          string
+	StartCreatedDate  string
+	EndCreatedDate    string
+	StartFinishedDate string
+	EndFinishedDate   string
+	StartDueDate      string
+	EndDueDate        string
+	Due               string
+	TZ                string
+	Title             string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	// The presence of the cursor parameter selects the keyset listing, so an
+	// empty value asks for its first page.
+	_, cursorMode := values["cursor"]
+
+	filter := queryParams{
+		Cursor:            values.Get("cursor"),
+		CursorMode:        cursorMode,
+		Page:              values.Get("page"),
+		Rows:              values.Get("rows"),
+		OrderBy:           values.Get("orderBy"),
+		ProjectID:         values.Get("project_id"),
+		AssignedTo:        values.Get("assigned_to"),
+		CreatedBy:         values.Get("created_by"),
+		ParentID:          values.Get("parent_id"),
+		Finished:          values.Get("finished"),
+		Status:            values.Get("status"),
+		Open:              values.Get("open"),
+		Priority:          values.Get("priority"),
+		StartCreatedDate:  values.Get("start_created_date"),
+		EndCreatedDate:    values.Get("end_created_date"),
+		StartFinishedDate: values.Get("start_finished_date"),
+		EndFinishedDate:   values.Get("end_finished_date"),
+		StartDueDate:      values.Get("start_due_date"),
+		EndDueDate:        values.Get("end_due_date"),
+		Due:               values.Get("due"),
+		TZ:                values.Get("tz"),
+		Title:             values.Get("title"),
+	}
+
+	return filter
+}
+
+// parseFilter converts the query string into a filter. Dates use RFC3339.
+// The due parameter picks the open tasks that are overdue, due today or due
+// this week, where the day and the week are those of the tz location (an
+// IANA name, UTC by default).
+func parseFilter(qp queryParams) (taskbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter taskbus.QueryFilter
+
+	ids := []struct {
+		field string
+		value string
+		dest  **int
+	}{
+		{"project_id", qp.ProjectID, &filter.ProjectID},
+		{"assigned_to", qp.AssignedTo, &filter.AssignedTo},
+		{"created_by", qp.CreatedBy, &filter.CreatedBy},
+		{"parent_id", qp.ParentID, &filter.ParentID},
+	}
+	for _, id := range ids {
+		if id.value == "" {
+			continue
+		}
+		v, err := strconv.Atoi(id.value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError(id.field, err)...)
+			continue
+		}
+		*id.dest = &v
+	}
+
+	bools := []struct {
+		field string
+		value string
+		dest  **bool
+	}{
+		{"finished", qp.Finished, &filter.Finished},
+		{"open", qp.Open, &filter.Open},
+	}
+	for _, b := range bools {
+		if b.value == "" {
+			continue
+		}
+		v, err := strconv.ParseBool(b.value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError(b.field, err)...)
+			continue
+		}
+		*b.dest = &v
+	}
+
+	if qp.Status != "" {
+		status, err := taskbus.ParseStatus(qp.Status)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError("status", err)...)
+		} else {
+			filter.Status = &status
+		}
+	}
+
+	if qp.Priority != "" {
+		priority, err := taskbus.ParsePriority(qp.Priority)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError("priority", err)...)
+		} else {
+			filter.Priority = &priority
+		}
+	}
+
+	dates := []struct {
+		field string
+		value string
+		dest  **time.Time
+	}{
+		{"start_created_date", qp.StartCreatedDate, &filter.StartCreatedDate},
+		{"end_created_date", qp.EndCreatedDate, &filter.EndCreatedDate},
+		{"start_finished_date", qp.StartFinishedDate, &filter.StartFinishedDate},
+		{"end_finished_date", qp.EndFinishedDate, &filter.EndFinishedDate},
+		{"start_due_date", qp.StartDueDate, &filter.StartDueDate},
+		{"end_due_date", qp.EndDueDate, &filter.EndDueDate},
+	}
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, date.value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError(date.field, err)...)
+			continue
+		}
+		*date.dest = &t
+	}
+
+	if qp.Title != "" {
+		filter.Title = &qp.Title
+	}
+
+	loc, err := time.LoadLocation(qp.TZ)
+	if err != nil {
+		fieldErrors = append(fieldErrors, errs.NewFieldsError("tz", err)...)
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	switch qp.Due {
+	case "":
+	case "overdue":
+		filter = filter.Overdue(now)
+	case "today":
+		filter = filter.DueToday(now)
+	case "week":
+		filter = filter.DueThisWeek(now)
+	default:
+		fieldErrors = append(fieldErrors, errs.NewFieldsError("due", fmt.Errorf("invalid due %q, use overdue, today or week", qp.Due))...)
+	}
+
+	if fieldErrors != nil {
+		return taskbus.QueryFilter{}, fieldErrors
+	}
+
+	return filter, nil
+}
@@ -1,19 +1,24 @@
 package taskapp
 
 import (
+	"TODO-list/app/sdk/errs"
 	"TODO-list/business/domain/taskbus"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"time"
 )
 
-// NewTask represents a new task to be created.
+// NewTask represents a new task to be created. The due date is an RFC3339
+// time, so it always carries the zone of the caller.
 type NewTask struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	ProjectID   int    `json:"project_id"`
-	CreatedBy   int    `json:"created_by"`
-	AssignedTo  *int   `json:"assigned_to"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   int        `json:"project_id"`
+	AssignedTo  *int       `json:"assigned_to"`
+	Priority    string     `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueAt       *time.Time `json:"due_at"`
+	ParentID    *int       `json:"parent_id"`
 }
 
 // Decode implements the decoder interface.
@@ -21,31 +26,70 @@ func (nt *NewTask) Decode(data []byte) error {
 	return json.Unmarshal(data, &nt)
 }
 
-// toBusNewTask converts a NewTask from the application layer to the business layer.
-func toBusNewTask(nt NewTask) taskbus.NewTask {
+// Validate checks the fields of the new task. A new task can not be due in
+// the past.
+func (nt NewTask) Validate() error {
+	if err := errs.Check(nt); err != nil {
+		return err
+	}
+
+	if nt.DueAt != nil && nt.DueAt.Before(time.Now()) {
+		return errs.NewFieldsError("due_at", errors.New("due_at must be in the future"))
+	}
+
+	return nil
+}
+
+// toBusNewTask converts a NewTask from the application layer to the business
+// layer. The creator is the authenticated user.
+func toBusNewTask(nt NewTask, createdBy int) (taskbus.NewTask, error) {
 	assignedTo := sql.NullInt32{Int32: 1}
 	if nt.AssignedTo != nil {
 		assignedTo = sql.NullInt32{Int32: int32(*nt.AssignedTo), Valid: true}
 	}
+
+	priority, err := toBusPriority(nt.Priority)
+	if err != nil {
+		return taskbus.NewTask{}, err
+	}
+
 	return taskbus.NewTask{
 		Title:       nt.Title,
 		Description: nt.Description,
 		ProjectID:   nt.ProjectID,
-		CreatedBy:   nt.CreatedBy,
+		CreatedBy:   createdBy,
 		AssignedTo:  assignedTo,
-	}
+		Priority:    priority,
+		DueAt:       toBusDueAt(nt.DueAt),
+		ParentID:    toBusParentID(nt.ParentID),
+	}, nil
 }
 
 // Task represents a task in the system.
 type Task struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	ProjectID   int       `json:"project_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	FinishedAt  time.Time `json:"finished_at"`
-	CreatedBy   int       `json:"created_by"`
-	AssignedTo  int       `json:"assigned_to"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   int        `json:"project_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  time.Time  `json:"finished_at"`
+	CreatedBy   int        `json:"created_by"`
+	AssignedTo  int        `json:"assigned_to"`
+	Status      string     `json:"status"`
+	ReopenCount int        `json:"reopen_count"`
+	Priority    string     `json:"priority"`
+	DueAt       *time.Time `json:"due_at"`
+	LateSeconds int64      `json:"late_seconds"`
+	ParentID    int        `json:"parent_id,omitempty"`
+	Progress    *Progress  `json:"progress,omitempty"`
+}
+
+// Progress summarizes the direct subtasks of a task. Cancelled subtasks are
+// not counted.
+type Progress struct {
+	Done    int `json:"done"`
+	Total   int `json:"total"`
+	Percent int `json:"percent"`
 }
 
 // Encode implements the web.Encoder interface for the Task type.
@@ -55,7 +99,23 @@ func (t Task) Encode() ([]byte, string, error) {
 }
 
 // toAppTask converts a task from the business layer to the application layer.
-func toAppTask(taskBus taskbus.Task) Task {
+// A task without a due date has a null due_at, and one without subtasks has
+// no progress.
+func toAppTask(taskBus taskbus.Task, progress taskbus.Progress) Task {
+	var dueAt *time.Time
+	if taskBus.DueAt.Valid {
+		dueAt = &taskBus.DueAt.Time
+	}
+
+	var appProgress *Progress
+	if progress.Total > 0 {
+		appProgress = &Progress{
+			Done:    progress.Done,
+			Total:   progress.Total,
+			Percent: progress.Done * 100 / progress.Total,
+		}
+	}
+
 	return Task{
 		ID:          taskBus.ID,
 		Title:       taskBus.Title,
@@ -65,6 +125,13 @@ func toAppTask(taskBus taskbus.Task) Task {
 		FinishedAt:  taskBus.FinishedAt.Time,
 		CreatedBy:   taskBus.CreatedBy,
 		AssignedTo:  int(taskBus.AssignedTo.Int32),
+		Status:      taskBus.Status.String(),
+		ReopenCount: taskBus.ReopenCount,
+		Priority:    taskBus.Priority.String(),
+		DueAt:       dueAt,
+		LateSeconds: int64(taskBus.LateBy / time.Second),
+		ParentID:    int(taskBus.ParentID.Int32),
+		Progress:    appProgress,
 	}
 }
 
@@ -77,20 +144,26 @@ func (ts Tasks) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
-// toAppTasks converts a slice of business layer tasks to application layer tasks.
-func toAppTasks(tasksBus []taskbus.Task) Tasks {
+// toAppTasks converts a slice of business layer tasks to application layer
+// tasks along with the progress of their subtasks.
+func toAppTasks(tasksBus []taskbus.Task, progress map[int]taskbus.Progress) Tasks {
 	tasksApp := make(Tasks, len(tasksBus))
 	for i, taskBus := range tasksBus {
-		tasksApp[i] = toAppTask(taskBus)
+		tasksApp[i] = toAppTask(taskBus, progress[taskBus.ID])
 	}
 	return tasksApp
 }
 
-// UpdateTask represents a task with updates to be applied.
+// UpdateTask represents a task with updates to be applied. An empty priority
+// keeps the current one, a null due_at removes the due date and a null
+// parent_id makes the task a top level one.
 type UpdateTask struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	AssignedTo  *int   `json:"assigned_to"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	AssignedTo  *int       `json:"assigned_to"`
+	Priority    string     `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueAt       *time.Time `json:"due_at"`
+	ParentID    *int       `json:"parent_id"`
 }
 
 // Decode implements the decoder interface.
@@ -98,16 +171,170 @@ func (ut *UpdateTask) Decode(data []byte) error {
 	return json.Unmarshal(data, &ut)
 }
 
+// Validate checks the fields of the update. Unlike a new task, the due date
+// may be in the past so an overdue task can be edited.
+func (ut UpdateTask) Validate() error {
+	return errs.Check(ut)
+}
+
 // toBusUpdateTask converts an UpdateTask from the application layer to the business layer.
-func toBusUpdateTask(ut UpdateTask) taskbus.UpdateTask {
+func toBusUpdateTask(ut UpdateTask) (taskbus.UpdateTask, error) {
 	assignedTo := sql.NullInt32{Valid: false}
 	if ut.AssignedTo != nil {
 		assignedTo = sql.NullInt32{Int32: int32(*ut.AssignedTo), Valid: true}
 	}
 
+	priority, err := toBusPriority(ut.Priority)
+	if err != nil {
+		return taskbus.UpdateTask{}, err
+	}
+
 	return taskbus.UpdateTask{
 		Title:       ut.Title,
 		Description: ut.Description,
 		AssignedTo:  assignedTo,
+		Priority:    priority,
+		DueAt:       toBusDueAt(ut.DueAt),
+		ParentID:    toBusParentID(ut.ParentID),
+	}, nil
+}
+
+// toBusPriority parses the priority, leaving it empty when none was given so
+// the business layer picks the default.
+func toBusPriority(value string) (taskbus.Priority, error) {
+	if value == "" {
+		return taskbus.Priority{}, nil
+	}
+
+	priority, err := taskbus.ParsePriority(value)
+	if err != nil {
+		return taskbus.Priority{}, errs.NewFieldsError("priority", err)
+	}
+
+	return priority, nil
+}
+
+func toBusParentID(parentID *int) sql.NullInt32 {
+	if parentID == nil {
+		return sql.NullInt32{}
+	}
+
+	return sql.NullInt32{Int32: int32(*parentID), Valid: true}
+}
+
+func toBusDueAt(dueAt *time.Time) sql.NullTime {
+	if dueAt == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *dueAt, Valid: true}
+}
+
+// NewTransition represents the status a task is moved to.
+type NewTransition struct {
+	Status string `json:"status" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (nt *NewTransition) Decode(data []byte) error {
+	return json.Unmarshal(data, &nt)
+}
+
+// Validate checks the fields of the transition.
+func (nt NewTransition) Validate() error {
+	return errs.Check(nt)
+}
+
+// Reopen represents why a finished task is reopened.
+type Reopen struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// Decode implements the decoder interface.
+func (ro *Reopen) Decode(data []byte) error {
+	return json.Unmarshal(data, &ro)
+}
+
+// Validate checks the fields of the reopening.
+func (ro Reopen) Validate() error {
+	return errs.Check(ro)
+}
+
+// Transition represents a change of status of a task.
+type Transition struct {
+	ID        int       `json:"id"`
+	TaskID    int       `json:"task_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedBy int       `json:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// toAppTransition converts a transition from the business layer to the application layer.
+func toAppTransition(tr taskbus.Transition) Transition {
+	return Transition{
+		ID:        tr.ID,
+		TaskID:    tr.TaskID,
+		From:      tr.From.String(),
+		To:        tr.To.String(),
+		ChangedBy: int(tr.ChangedBy.Int32),
+		ChangedAt: tr.ChangedAt,
+		Reason:    tr.Reason,
+	}
+}
+
+// Transitions represents the status history of a task.
+type Transitions []Transition
+
+// Encode implements the web.Encoder interface for the Transitions type.
+func (trs Transitions) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(trs)
+	return data, "application/json", err
+}
+
+// toAppTransitions converts a slice of business layer transitions to application layer transitions.
+func toAppTransitions(trs []taskbus.Transition) Transitions {
+	app := make(Transitions, len(trs))
+	for i, tr := range trs {
+		app[i] = toAppTransition(tr)
+	}
+	return app
+}
+
+// NewDependency represents the task blocking another one.
+type NewDependency struct {
+	BlockerID int `json:"blocker_id" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (nd *NewDependency) Decode(data []byte) error {
+	return json.Unmarshal(data, &nd)
+}
+
+// Validate checks the fields of the dependency.
+func (nd NewDependency) Validate() error {
+	return errs.Check(nd)
+}
+
+// Dependency represents a task blocked by another one.
+type Dependency struct {
+	TaskID    int       `json:"task_id"`
+	BlockerID int       `json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Encode implements the web.Encoder interface for the Dependency type.
+func (d Dependency) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(d)
+	return data, "application/json", err
+}
+
+// toAppDependency converts a dependency from the business layer to the application layer.
+func toAppDependency(dep taskbus.Dependency) Dependency {
+	return Dependency{
+		TaskID:    dep.TaskID,
+		BlockerID: dep.BlockerID,
+		CreatedAt: dep.CreatedAt,
 	}
 }
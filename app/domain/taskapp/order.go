@@ -0,0 +1,18 @@
+package taskapp
+
+import (
+	"TODO-list/business/domain/taskbus"
+)
+
+var orderByFields = map[string]string{
+	"id":           taskbus.OrderByID,
+	"title":        taskbus.OrderByTitle,
+	"project_id":   taskbus.OrderByProjectID,
+	"created_at":   taskbus.OrderByCreatedAt,
+	"finished_at":  taskbus.OrderByFinishedAt,
+	"created_by":   taskbus.OrderByCreatedBy,
+	"assigned_to":  taskbus.OrderByAssignedTo,
+	"reopen_count": taskbus.OrderByReopenCount,
+	"priority":     taskbus.OrderByPriority,
+	"due_at":       taskbus.OrderByDueAt,
+}
@@ -1,7 +1,11 @@
 package taskapp
 
 import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/cursor"
+	"TODO-list/app/sdk/mid"
 	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/userbus"
 	"TODO-list/foundation/logger"
 	"TODO-list/foundation/web"
 	"net/http"
@@ -9,19 +13,37 @@ import (
 
 // Config holds the configuration dependencies for the application.
 type Config struct {
+	UserBus *userbus.Business
 	TaskBus *taskbus.Business
 	Logger  *logger.Logger
+	Cursors *cursor.Signer
+	Auth    *auth.Auth
 }
 
 // Routes sets up the HTTP routes for the task-related API endpoints.
 func Routes(web *web.App, cfg Config) {
-	app := newApp(cfg.TaskBus)
+	authen := mid.Authenticate(cfg.Auth, cfg.UserBus)
 
-	web.HandlerFunc(http.MethodPost, "", "/api/tasks", app.Create, nil)
-	web.HandlerFunc(http.MethodGet, "", "/api/tasks", app.Query, nil)
-	web.HandlerFunc(http.MethodGet, "", "/api/tasks/{id}", app.QueryByID, nil)
-	web.HandlerFunc(http.MethodPut, "", "/api/tasks/{id}", app.Update, nil)
-	web.HandlerFunc(http.MethodDelete, "", "/api/tasks/{id}", app.Delete, nil)
-	web.HandlerFunc(http.MethodPut, "", "/api/tasks/finish/{id}", app.Finish, nil)
+	app := newApp(cfg.TaskBus, cfg.Cursors)
 
+	ruleAny := mid.Authorize(cfg.Auth, auth.RuleAny)
+	ruleAdminOrSubject := mid.AuthorizeTask(cfg.Auth, cfg.TaskBus, auth.RuleAdminOrSubject)
+
+	web.HandlerFunc(http.MethodPost, "", "/api/tasks", app.Create, authen, ruleAny)
+	web.HandlerFunc(http.MethodGet, "", "/api/tasks", app.Query, authen, ruleAny)
+	web.HandlerFunc(http.MethodGet, "", "/api/tasks/{id}", app.QueryByID, authen, ruleAny)
+	web.HandlerFunc(http.MethodPut, "", "/api/tasks/{id}", app.Update, authen, ruleAdminOrSubject)
+	web.HandlerFunc(http.MethodDelete, "", "/api/tasks/{id}", app.Delete, authen, ruleAdminOrSubject)
+	web.HandlerFunc(http.MethodPut, "", "/api/tasks/finish/{id}", app.Finish, authen, ruleAdminOrSubject)
+	web.HandlerFunc(http.MethodPut, "", "/api/tasks/reopen/{id}", app.Reopen, authen, ruleAdminOrSubject)
+	web.HandlerFunc(http.MethodPost, "", "/api/tasks/{id}/transition", app.Transition, authen, ruleAdminOrSubject)
+	web.HandlerFunc(http.MethodGet, "", "/api/tasks/{id}/transitions", app.QueryTransitions, authen, ruleAny)
+	web.HandlerFunc(http.MethodGet, "", "/api/tasks/{id}/subtasks", app.QuerySubtasks, authen, ruleAny)
+	web.HandlerFunc(http.MethodGet, "", "/api/tasks/{id}/dependencies", app.QueryBlockers, authen, ruleAny)
+	web.HandlerFunc(http.MethodPost, "", "/api/tasks/{id}/dependencies", app.AddDependency, authen, ruleAdminOrSubject)
+	web.HandlerFunc(http.MethodDelete, "", "/api/tasks/{id}/dependencies/{blocker_id}", app.RemoveDependency, authen, ruleAdminOrSubject)
+
+	// The critical path is worked out from the task dependencies, so it is
+	// served here rather than by the project routes.
+	web.HandlerFunc(http.MethodGet, "", "/api/project/{id}/critical-path", app.CriticalPath, authen, ruleAny)
 }
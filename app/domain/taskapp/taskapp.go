@@ -1,11 +1,19 @@
 package taskapp
 
 import (
+	"TODO-list/app/sdk/cursor"
 	"TODO-list/app/sdk/errs"
+	"TODO-list/app/sdk/mid"
+	"TODO-list/app/sdk/query"
+	"TODO-list/business/domain/projectbus"
 	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 	"TODO-list/foundation/web"
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 )
@@ -13,12 +21,15 @@ import (
 // App handles the application layer of tasks.
 type App struct {
 	taskBus *taskbus.Business
+	cursors *cursor.Signer
 }
 
-// newApp creates a new instance of App with the task business logic layer.
-func newApp(taskBus *taskbus.Business) *App {
+// newApp creates a new instance of App with the task business logic layer
+// and the signer of the listing cursors.
+func newApp(taskBus *taskbus.Business, cursors *cursor.Signer) *App {
 	return &App{
 		taskBus: taskBus,
+		cursors: cursors,
 	}
 }
 
@@ -29,25 +40,143 @@ func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	if app.CreatedBy == 0 {
-		return errs.New(errs.InvalidArgument, fmt.Errorf("created_by is required"))
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	nt, err := toBusNewTask(app, userID)
+	if err != nil {
+		return err.(errs.FieldErrors)
 	}
 
-	taskBus, err := a.taskBus.Create(ctx, toBusNewTask(app))
+	taskBus, err := a.taskBus.Create(ctx, nt)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
-	return toAppTask(taskBus)
+	// A new task has no subtasks yet.
+	return toAppTask(taskBus, taskbus.Progress{})
 }
 
-// Query retrieves all tasks.
+// Query retrieves a page of the tasks matching the query string.
 func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
-	tasksBus, err := a.taskBus.Query(ctx)
+	qp := parseQueryParams(r)
+	if qp.CursorMode {
+		return a.queryAfter(ctx, qp)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	return a.query(ctx, qp, filter)
+}
+
+// QuerySubtasks retrieves a page of the direct subtasks of a task. It takes
+// the same query string as the task listing.
+func (a *App) QuerySubtasks(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if _, err := a.taskBus.QueryByID(ctx, id); err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	qp := parseQueryParams(r)
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+	filter.ParentID = &id
+
+	return a.query(ctx, qp, filter)
+}
+
+// query retrieves the page of the tasks matching the filter with its total.
+func (a *App) query(ctx context.Context, qp queryParams, filter taskbus.QueryFilter) web.Encoder {
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldsError("page", err)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, taskbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldsError("orderBy", err)
+	}
+
+	tasksBus, err := a.taskBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	total, err := a.taskBus.Count(ctx, filter)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
-	return toAppTasks(tasksBus)
+
+	progress, err := a.progress(ctx, tasksBus...)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return query.NewResult(toAppTasks(tasksBus, progress), total, page)
+}
+
+// queryAfter retrieves the tasks following the cursor in (created_at, id)
+// order. It returns no total, which would cost a scan of the table, and the
+// next_cursor is left empty once the last task was returned.
+func (a *App) queryAfter(ctx context.Context, qp queryParams) web.Encoder {
+	if qp.Page != "" || qp.OrderBy != "" {
+		return errs.NewFieldsError("cursor", errors.New("page and orderBy can not be used with cursor"))
+	}
+
+	page, err := page.Parse("", qp.Rows)
+	if err != nil {
+		return errs.NewFieldsError("rows", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	var after *taskbus.Cursor
+	if qp.Cursor != "" {
+		var token cursorToken
+		if err := a.cursors.Decode(qp.Cursor, &token); err != nil {
+			return errs.NewFieldsError("cursor", err)
+		}
+		after = &taskbus.Cursor{CreatedAt: token.CreatedAt, ID: token.ID}
+	}
+
+	// One extra task tells whether there is a next page.
+	rows := page.RowsPerPage()
+	tasksBus, err := a.taskBus.QueryAfter(ctx, filter, after, rows+1)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	var next string
+	if len(tasksBus) > rows {
+		tasksBus = tasksBus[:rows]
+
+		next, err = a.cursors.Encode(toCursorToken(taskbus.CursorOf(tasksBus[rows-1])))
+		if err != nil {
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	progress, err := a.progress(ctx, tasksBus...)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return query.NewCursorResult(toAppTasks(tasksBus, progress), next, rows)
 }
 
 // QueryByID retrieves a task by its ID.
@@ -59,10 +188,15 @@ func (a *App) QueryByID(ctx context.Context, r *http.Request) web.Encoder {
 
 	taskBus, err := a.taskBus.QueryByID(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
-	return toAppTask(taskBus)
+	progress, err := a.progress(ctx, taskBus)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppTask(taskBus, progress[taskBus.ID])
 }
 
 // Update modifies an existing task and returns the updated task.
@@ -77,9 +211,14 @@ func (a *App) Update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	err = a.taskBus.Update(ctx, id, toBusUpdateTask(ut))
+	utBus, err := toBusUpdateTask(ut)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	err = a.taskBus.Update(ctx, id, utBus)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
@@ -94,23 +233,243 @@ func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
 
 	err = a.taskBus.Delete(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
 }
 
-// Finish marks a task as completed.
+// Finish marks a task as completed. With subtasks=true in the query string
+// its open subtasks are finished too; otherwise they must be closed first.
 func (a *App) Finish(ctx context.Context, r *http.Request) web.Encoder {
 	id, err := strconv.Atoi(web.Param(r, "id"))
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	err = a.taskBus.Finish(ctx, id)
+	var subtasks bool
+	if v := r.URL.Query().Get("subtasks"); v != "" {
+		if subtasks, err = strconv.ParseBool(v); err != nil {
+			return errs.NewFieldsError("subtasks", err)
+		}
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+	changedBy := sql.NullInt32{Int32: int32(userID), Valid: true}
+
+	if subtasks {
+		err = a.taskBus.FinishWithSubtasks(ctx, id, changedBy)
+	} else {
+		err = a.taskBus.Finish(ctx, id, changedBy)
+	}
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return nil
+}
+
+// Transition moves a task to another status of the workflow and returns the
+// updated task.
+func (a *App) Transition(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	var nt NewTransition
+	if err := web.Decode(r, &nt); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	status, err := taskbus.ParseStatus(nt.Status)
+	if err != nil {
+		return errs.NewFieldsError("status", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	task, err := a.taskBus.Transition(ctx, id, status, sql.NullInt32{Int32: int32(userID), Valid: true})
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	progress, err := a.progress(ctx, task)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppTask(task, progress[task.ID])
+}
+
+// Reopen moves a finished task back to todo and returns the updated task.
+func (a *App) Reopen(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	var ro Reopen
+	if err := web.Decode(r, &ro); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	task, err := a.taskBus.Reopen(ctx, id, ro.Reason, sql.NullInt32{Int32: int32(userID), Valid: true})
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	progress, err := a.progress(ctx, task)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppTask(task, progress[task.ID])
+}
+
+// QueryTransitions lists the status changes of a task, oldest first.
+func (a *App) QueryTransitions(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	trs, err := a.taskBus.QueryTransitions(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppTransitions(trs)
+}
+
+// AddDependency records that a task is blocked by another one and returns
+// the link.
+func (a *App) AddDependency(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	var nd NewDependency
+	if err := web.Decode(r, &nd); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	dep, err := a.taskBus.AddDependency(ctx, id, nd.BlockerID)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppDependency(dep)
+}
+
+// RemoveDependency removes the link between a task and its blocker.
+func (a *App) RemoveDependency(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	blockerID, err := strconv.Atoi(web.Param(r, "blocker_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.taskBus.RemoveDependency(ctx, id, blockerID); err != nil {
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
 }
+
+// QueryBlockers lists the tasks blocking a task.
+func (a *App) QueryBlockers(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	blockers, err := a.taskBus.QueryBlockers(ctx, id)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	progress, err := a.progress(ctx, blockers...)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppTasks(blockers, progress)
+}
+
+// CriticalPath lists the longest chain of open tasks of a project where
+// each task is blocked by the one before it.
+func (a *App) CriticalPath(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tasks, err := a.taskBus.CriticalPath(ctx, id)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	progress, err := a.progress(ctx, tasks...)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return toAppTasks(tasks, progress)
+}
+
+// progress retrieves the progress of the subtasks of the tasks.
+func (a *App) progress(ctx context.Context, tasks ...taskbus.Task) (map[int]taskbus.Progress, error) {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	return a.taskBus.QueryProgress(ctx, ids)
+}
+
+// errCode maps the business errors to the code returned to the caller.
+func errCode(err error) errs.ErrCode {
+	switch {
+	case errors.Is(err, taskbus.ErrNotFound),
+		errors.Is(err, projectbus.ErrNotFound),
+		errors.Is(err, userbus.ErrNotFound),
+		errors.Is(err, taskbus.ErrDependencyNotFound):
+		return errs.NotFound
+	case errors.Is(err, taskbus.ErrDependencyExists):
+		return errs.AlreadyExists
+	case errors.Is(err, taskbus.ErrReasonRequired):
+		return errs.InvalidArgument
+	case errors.Is(err, projectbus.ErrProjectInactive),
+		errors.Is(err, userbus.ErrUserInactive),
+		errors.Is(err, taskbus.ErrAssigneeNotMember),
+		errors.Is(err, taskbus.ErrInvalidTransition),
+		errors.Is(err, taskbus.ErrInvalidParent),
+		errors.Is(err, taskbus.ErrParentCycle),
+		errors.Is(err, taskbus.ErrOpenSubtasks),
+		errors.Is(err, taskbus.ErrHasSubtasks),
+		errors.Is(err, taskbus.ErrInvalidBlocker),
+		errors.Is(err, taskbus.ErrDependencyCycle),
+		errors.Is(err, taskbus.ErrOpenBlockers),
+		errors.Is(err, taskbus.ErrBlocksDoneTasks):
+		return errs.FailedPrecondition
+	}
+
+	return errs.InternalOnlyLog
+}
@@ -0,0 +1,86 @@
+package userapp
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/domain/userbus"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	Active           string
+	Name             string
+	EmailDomain      string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		Active:           values.Get("active"),
+		Name:             values.Get("name"),
+		EmailDomain:      values.Get("email_domain"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+
+	return filter
+}
+
+// parseFilter converts the query string into a filter. Dates use RFC3339.
+func parseFilter(qp queryParams) (userbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter userbus.QueryFilter
+
+	if qp.Active != "" {
+		active, err := strconv.ParseBool(qp.Active)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError("active", err)...)
+		} else {
+			filter.Active = &active
+		}
+	}
+
+	if qp.Name != "" {
+		filter.Name = &qp.Name
+	}
+
+	if qp.EmailDomain != "" {
+		filter.EmailDomain = &qp.EmailDomain
+	}
+
+	dates := []struct {
+		field string
+		value string
+		dest  **time.Time
+	}{
+		{"start_created_date", qp.StartCreatedDate, &filter.StartCreatedDate},
+		{"end_created_date", qp.EndCreatedDate, &filter.EndCreatedDate},
+	}
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, date.value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.NewFieldsError(date.field, err)...)
+			continue
+		}
+		*date.dest = &t
+	}
+
+	if fieldErrors != nil {
+		return userbus.QueryFilter{}, fieldErrors
+	}
+
+	return filter, nil
+}
@@ -1,6 +1,7 @@
 package userapp
 
 import (
+	"TODO-list/app/sdk/errs"
 	"TODO-list/business/domain/userbus"
 	"encoding/json"
 	"time"
@@ -8,8 +9,9 @@ import (
 
 // NewUser represents the input data required to create a new user.
 type NewUser struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
 }
 
 // Decode decodes a JSON byte slice into a NewUser struct.
@@ -17,11 +19,17 @@ func (nu *NewUser) Decode(data []byte) error {
 	return json.Unmarshal(data, &nu)
 }
 
+// Validate checks the fields of the new user.
+func (nu NewUser) Validate() error {
+	return errs.Check(nu)
+}
+
 // toBusNewUser converts a NewUser struct from the application layer to the business layer representation.
 func toBusNewUser(nu NewUser) userbus.NewUser {
 	return userbus.NewUser{
-		Name:  nu.Name,
-		Email: nu.Email,
+		Name:     nu.Name,
+		Email:    nu.Email,
+		Password: nu.Password,
 	}
 }
 
@@ -30,6 +38,7 @@ type User struct {
 	ID        int       `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Role      string    `json:"role"`
 	Active    bool      `json:"active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -47,6 +56,7 @@ func toAppUser(userBus userbus.User) User {
 		ID:        userBus.ID,
 		Name:      userBus.Name,
 		Email:     userBus.Email,
+		Role:      userBus.Role.String(),
 		Active:    userBus.Active,
 		CreatedAt: userBus.CreatedAt.Time,
 		UpdatedAt: userBus.UpdatedAt.Time,
@@ -89,3 +99,18 @@ func toBusUpdateUser(uu UpdateUser) userbus.UpdateUser {
 		Email: uu.Email,
 	}
 }
+
+// UpdateRole represents the role given to a user.
+type UpdateRole struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// Decode decodes a JSON byte slice into an UpdateRole struct.
+func (ur *UpdateRole) Decode(data []byte) error {
+	return json.Unmarshal(data, &ur)
+}
+
+// Validate checks the fields of the role update.
+func (ur UpdateRole) Validate() error {
+	return errs.Check(ur)
+}
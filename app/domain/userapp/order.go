@@ -0,0 +1,12 @@
+package userapp
+
+import (
+	"TODO-list/business/domain/userbus"
+)
+
+var orderByFields = map[string]string{
+	"id":         userbus.OrderByID,
+	"name":       userbus.OrderByName,
+	"email":      userbus.OrderByEmail,
+	"created_at": userbus.OrderByCreatedAt,
+}
@@ -1,6 +1,9 @@
 package userapp
 
 import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/mid"
+	"TODO-list/business/domain/tokenbus"
 	"TODO-list/business/domain/userbus"
 	"TODO-list/foundation/logger"
 	"TODO-list/foundation/web"
@@ -9,18 +12,28 @@ import (
 
 // Config contains the dependencies required for initializing the user application.
 type Config struct {
-	UserBus *userbus.Business
-	Logger  *logger.Logger
+	UserBus  *userbus.Business
+	TokenBus *tokenbus.Business
+	Logger   *logger.Logger
+	Auth     *auth.Auth
 }
 
 // Routes sets up the HTTP routes for the user-related API endpoints.
 func Routes(app *web.App, cfg Config) {
-	appUser := newApp(cfg.UserBus)
+	authen := mid.Authenticate(cfg.Auth, cfg.UserBus)
 
+	appUser := newApp(cfg.UserBus, cfg.TokenBus)
+
+	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
+	ruleAdminOrSubject := mid.AuthorizeUser(cfg.Auth, auth.RuleAdminOrSubject)
+
+	// Signing up is the one route open to anonymous callers. It always
+	// creates a user with the user role.
 	app.HandlerFunc(http.MethodPost, "", "/api/users", appUser.Create, nil)
-	app.HandlerFunc(http.MethodGet, "", "/api/users", appUser.Query, nil)
-	app.HandlerFunc(http.MethodGet, "", "/api/users/{id}", appUser.QueryById, nil)
-	app.HandlerFunc(http.MethodGet, "", "/api/users/email/{email}", appUser.QueryByEmail, nil)
-	app.HandlerFunc(http.MethodPut, "", "/api/users/{id}", appUser.Update, nil)
-	app.HandlerFunc(http.MethodDelete, "", "/api/users/{id}", appUser.Delete, nil)
+	app.HandlerFunc(http.MethodGet, "", "/api/users", appUser.Query, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodGet, "", "/api/users/{id}", appUser.QueryById, authen, ruleAdminOrSubject)
+	app.HandlerFunc(http.MethodGet, "", "/api/users/email/{email}", appUser.QueryByEmail, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodPut, "", "/api/users/{id}", appUser.Update, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodPut, "", "/api/users/{id}/role", appUser.SetRole, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodDelete, "", "/api/users/{id}", appUser.Delete, authen, ruleAdmin)
 }
@@ -2,9 +2,14 @@ package userapp
 
 import (
 	"TODO-list/app/sdk/errs"
+	"TODO-list/app/sdk/query"
+	"TODO-list/business/domain/tokenbus"
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 	"TODO-list/foundation/web"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/mail"
@@ -13,13 +18,15 @@ import (
 
 // App represents the application layer for handling user-related requests.
 type App struct {
-	userBus *userbus.Business
+	userBus  *userbus.Business
+	tokenBus *tokenbus.Business
 }
 
 // newApp creates a new instance of the App, initializing it with the business layer (userBus).
-func newApp(userBus *userbus.Business) *App {
+func newApp(userBus *userbus.Business, tokenBus *tokenbus.Business) *App {
 	return &App{
-		userBus: userBus,
+		userBus:  userBus,
+		tokenBus: tokenBus,
 	}
 }
 
@@ -32,20 +39,42 @@ func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
 
 	userBus, err := a.userBus.Create(ctx, toBusNewUser(app))
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return toAppUser(userBus)
 }
 
-// Query retrieves a list of all users from the business layer.
+// Query retrieves a page of the users matching the query string.
 func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
-	usersBus, err := a.userBus.Query(ctx)
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldsError("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, userbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldsError("orderBy", err)
+	}
+
+	usersBus, err := a.userBus.Query(ctx, filter, orderBy, page)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
-	return toAppUsers(usersBus)
+	total, err := a.userBus.Count(ctx, filter)
+	if err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return query.NewResult(toAppUsers(usersBus), total, page)
 }
 
 // QueryById retrieves a specific user by its ID from the business layer.
@@ -57,7 +86,7 @@ func (a *App) QueryById(ctx context.Context, r *http.Request) web.Encoder {
 
 	userBus, err := a.userBus.QueryById(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return toAppUser(userBus)
@@ -76,7 +105,7 @@ func (a *App) QueryByEmail(ctx context.Context, r *http.Request) web.Encoder {
 	}
 	userBus, err := a.userBus.QueryByEmail(ctx, email)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return toAppUser(userBus)
@@ -96,13 +125,38 @@ func (a *App) Update(ctx context.Context, r *http.Request) web.Encoder {
 
 	err = a.userBus.Update(ctx, id, toBusUpdateUser(uu))
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
 }
 
-// Delete deactivates a user by their ID.
+// SetRole changes the role of a user.
+func (a *App) SetRole(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := strconv.Atoi(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	var ur UpdateRole
+	if err := web.Decode(r, &ur); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	role, err := userbus.ParseRole(ur.Role)
+	if err != nil {
+		return errs.NewFieldsError("role", err)
+	}
+
+	if err := a.userBus.SetRole(ctx, id, role); err != nil {
+		return errs.New(errCode(err), err)
+	}
+
+	return nil
+}
+
+// Delete deactivates a user by their ID and revokes their refresh tokens, so
+// the user is logged out once the access token expires.
 func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
 	id, err := strconv.Atoi(web.Param(r, "id"))
 	if err != nil {
@@ -111,8 +165,26 @@ func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
 
 	err = a.userBus.Deactivate(ctx, id)
 	if err != nil {
-		return errs.New(errs.InternalOnlyLog, err)
+		return errs.New(errCode(err), err)
+	}
+
+	if err := a.tokenBus.RevokeUser(ctx, id); err != nil {
+		return errs.New(errCode(err), err)
 	}
 
 	return nil
 }
+
+// errCode maps the business errors to the code returned to the caller.
+func errCode(err error) errs.ErrCode {
+	switch {
+	case errors.Is(err, userbus.ErrNotFound):
+		return errs.NotFound
+	case errors.Is(err, userbus.ErrUniqueEmail):
+		return errs.AlreadyExists
+	case errors.Is(err, userbus.ErrPasswordTooLong):
+		return errs.InvalidArgument
+	}
+
+	return errs.InternalOnlyLog
+}
@@ -0,0 +1,219 @@
+// Package auth provides authentication support. Tokens are JWTs signed with
+// RS256 and carry the id of the signing key in the kid header, so keys can be
+// rotated while tokens signed with the previous key are still in use.
+package auth
+
+import (
+	"TODO-list/business/domain/userbus"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Set of error variables for authentication and authorization.
+var (
+	// ErrInvalidToken is returned when the bearer token is missing,
+	// malformed, expired or not signed by one of the known keys.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrForbidden is returned when the claims do not satisfy a rule.
+	ErrForbidden = errors.New("attempted action is not allowed")
+)
+
+// The set of rules Authorize checks.
+const (
+	// RuleAny lets every authenticated user through.
+	RuleAny = "any"
+
+	// RuleAdminOnly requires the admin role.
+	RuleAdminOnly = "admin_only"
+
+	// RuleAdminOrSubject requires the admin role or the user to be one of
+	// the subjects of the target, such as the owner of a project.
+	RuleAdminOrSubject = "admin_or_subject"
+)
+
+// Claims represents the authorization claims transmitted via a JWT. The
+// subject is the id of the user.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// HasRole reports whether the claims carry the role.
+func (c Claims) HasRole(role userbus.Role) bool {
+	return slices.Contains(c.Roles, role.String())
+}
+
+// UserID returns the id of the user the token was issued to.
+func (c Claims) UserID() (int, error) {
+	id, err := strconv.Atoi(c.Subject)
+	if err != nil {
+		return 0, fmt.Errorf("subject %q: %w", c.Subject, ErrInvalidToken)
+	}
+
+	return id, nil
+}
+
+// KeyLookup declares a method set of behavior for looking up private and
+// public keys for JWT use. The return could be a PEM encoded string or a JWS
+// based key.
+type KeyLookup interface {
+	PrivateKey(kid string) (key string, err error)
+	PublicKey(kid string) (key string, err error)
+}
+
+// Config represents information required to initialize auth.
+type Config struct {
+	KeyLookup KeyLookup
+	Issuer    string
+}
+
+// Auth is used to authenticate clients. It can generate a token for a set of
+// user claims and recreate the claims by parsing the token.
+type Auth struct {
+	keyLookup KeyLookup
+	method    jwt.SigningMethod
+	parser    *jwt.Parser
+	issuer    string
+}
+
+// New creates an Auth to support authentication.
+func New(cfg Config) (*Auth, error) {
+	if cfg.KeyLookup == nil {
+		return nil, errors.New("key lookup is required")
+	}
+
+	a := Auth{
+		keyLookup: cfg.KeyLookup,
+		method:    jwt.SigningMethodRS256,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name}),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithExpirationRequired(),
+		),
+		issuer: cfg.Issuer,
+	}
+
+	return &a, nil
+}
+
+// Issuer provides the configured issuer used to authenticate tokens.
+func (a *Auth) Issuer() string {
+	return a.issuer
+}
+
+// NewClaims returns the claims of a token for the user, issued now and
+// expiring after ttl.
+func (a *Auth) NewClaims(usr userbus.User, ttl time.Duration) Claims {
+	now := time.Now()
+
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(usr.ID),
+			Issuer:    a.issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Roles: []string{usr.Role.String()},
+	}
+}
+
+// GenerateToken generates a signed JWT token string representing the user
+// Claims.
+func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
+	token := jwt.NewWithClaims(a.method, claims)
+	token.Header["kid"] = kid
+
+	privateKeyPEM, err := a.keyLookup.PrivateKey(kid)
+	if err != nil {
+		return "", fmt.Errorf("private key: %w", err)
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	if err != nil {
+		return "", fmt.Errorf("parsing private pem: %w", err)
+	}
+
+	str, err := token.SignedString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+
+	return str, nil
+}
+
+// Authenticate processes the token to validate the sender's token is valid.
+// The header must have the form "Bearer <token>".
+func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, error) {
+	scheme, tokenStr, found := strings.Cut(bearerToken, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return Claims{}, fmt.Errorf("expected authorization header format: Bearer <token>: %w", ErrInvalidToken)
+	}
+
+	var claims Claims
+	_, err := a.parser.ParseWithClaims(tokenStr, &claims, a.publicKey)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if _, err := claims.UserID(); err != nil {
+		return Claims{}, err
+	}
+
+	return claims, nil
+}
+
+// Authorize checks the rule against the claims. The subjects are the ids of
+// the users the target of the request belongs to.
+func (a *Auth) Authorize(claims Claims, rule string, subjects ...int) error {
+	switch rule {
+	case RuleAny:
+		return nil
+
+	case RuleAdminOnly:
+		if claims.HasRole(userbus.RoleAdmin) {
+			return nil
+		}
+
+	case RuleAdminOrSubject:
+		if claims.HasRole(userbus.RoleAdmin) {
+			return nil
+		}
+
+		userID, err := claims.UserID()
+		if err != nil {
+			return err
+		}
+		if slices.Contains(subjects, userID) {
+			return nil
+		}
+
+	default:
+		return fmt.Errorf("unknown rule %q", rule)
+	}
+
+	return fmt.Errorf("rule[%s]: %w", rule, ErrForbidden)
+}
+
+// publicKey returns the key that verifies the token, picked by its kid
+// header.
+func (a *Auth) publicKey(token *jwt.Token) (any, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("kid missing from header")
+	}
+
+	publicKeyPEM, err := a.keyLookup.PublicKey(kid)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	return jwt.ParseRSAPublicKeyFromPEM([]byte(publicKeyPEM))
+}
@@ -0,0 +1,153 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"TODO-list/app/sdk/auth"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/foundation/keystore"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kid = "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3HxDmBrc"
+
+func newAuth(t *testing.T, kids ...string) *auth.Auth {
+	t.Helper()
+
+	ks := keystore.New()
+	for _, kid := range kids {
+		privatePEM, err := keystore.GenerateRSAKey(rand.Reader)
+		require.NoError(t, err)
+		require.NoError(t, ks.Add(kid, privatePEM))
+	}
+
+	a, err := auth.New(auth.Config{KeyLookup: ks, Issuer: "tasks"})
+	require.NoError(t, err)
+
+	return a
+}
+
+func newClaims(userID int, issuer string, expires time.Time) auth.Claims {
+	return auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(userID),
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	a := newAuth(t, kid)
+
+	token, err := a.GenerateToken(kid, newClaims(7, "tasks", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	claims, err := a.Authenticate(context.Background(), "Bearer "+token)
+	require.NoError(t, err)
+
+	userID, err := claims.UserID()
+	require.NoError(t, err)
+	assert.Equal(t, 7, userID)
+}
+
+func TestAuthenticateRejects(t *testing.T) {
+	a := newAuth(t, kid)
+	other := newAuth(t, kid)
+
+	valid, err := a.GenerateToken(kid, newClaims(7, "tasks", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	expired, err := a.GenerateToken(kid, newClaims(7, "tasks", time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	wrongIssuer, err := a.GenerateToken(kid, newClaims(7, "other", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	foreign, err := other.GenerateToken(kid, newClaims(7, "tasks", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	parts := strings.Split(valid, ".")
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","iss":"tasks","exp":4102444800}`)) + "." + parts[2]
+
+	tests := map[string]string{
+		"missing":     "",
+		"scheme":      "Basic " + valid,
+		"expired":     "Bearer " + expired,
+		"issuer":      "Bearer " + wrongIssuer,
+		"foreign key": "Bearer " + foreign,
+		"tampered":    "Bearer " + tampered,
+		"not a token": "Bearer abc",
+	}
+
+	for name, header := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.Authenticate(context.Background(), header)
+			assert.ErrorIs(t, err, auth.ErrInvalidToken)
+		})
+	}
+}
+
+func TestAuthenticateUnknownKID(t *testing.T) {
+	signer := newAuth(t, "retired")
+	a := newAuth(t, kid)
+
+	token, err := signer.GenerateToken("retired", newClaims(7, "tasks", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	_, err = a.Authenticate(context.Background(), "Bearer "+token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestAuthorize(t *testing.T) {
+	a := newAuth(t, kid)
+
+	admin := a.NewClaims(userbus.User{ID: 1, Role: userbus.RoleAdmin}, time.Hour)
+	user := a.NewClaims(userbus.User{ID: 2, Role: userbus.RoleUser}, time.Hour)
+
+	tests := map[string]struct {
+		claims   auth.Claims
+		rule     string
+		subjects []int
+		allowed  bool
+	}{
+		"any":                {user, auth.RuleAny, nil, true},
+		"admin only":         {admin, auth.RuleAdminOnly, nil, true},
+		"admin only user":    {user, auth.RuleAdminOnly, nil, false},
+		"subject admin":      {admin, auth.RuleAdminOrSubject, []int{9}, true},
+		"subject":            {user, auth.RuleAdminOrSubject, []int{9, 2}, true},
+		"subject other user": {user, auth.RuleAdminOrSubject, []int{9}, false},
+		"no roles":           {newClaims(2, "tasks", time.Now().Add(time.Hour)), auth.RuleAdminOnly, nil, false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := a.Authorize(tt.claims, tt.rule, tt.subjects...)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, auth.ErrForbidden)
+		})
+	}
+
+	assert.Error(t, a.Authorize(admin, "unknown"))
+}
+
+func TestNewClaimsRoundTrip(t *testing.T) {
+	a := newAuth(t, kid)
+
+	token, err := a.GenerateToken(kid, a.NewClaims(userbus.User{ID: 3, Role: userbus.RoleAdmin}, time.Hour))
+	require.NoError(t, err)
+
+	claims, err := a.Authenticate(context.Background(), "Bearer "+token)
+	require.NoError(t, err)
+	assert.True(t, claims.HasRole(userbus.RoleAdmin))
+}
@@ -0,0 +1,45 @@
+package cursor_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"TODO-list/app/sdk/cursor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type position struct {
+	ID int `json:"id"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+
+	token, err := signer.Encode(position{ID: 42})
+	require.NoError(t, err)
+
+	var got position
+	require.NoError(t, signer.Decode(token, &got))
+	assert.Equal(t, 42, got.ID)
+}
+
+func TestTampered(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+
+	token, err := signer.Encode(position{ID: 42})
+	require.NoError(t, err)
+
+	_, sig, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"id":43}`)) + "." + sig
+
+	other, err := cursor.NewSigner([]byte("other")).Encode(position{ID: 42})
+	require.NoError(t, err)
+
+	for _, token := range []string{forged, other, "", "abc", "!!.!!"} {
+		var got position
+		assert.ErrorIs(t, signer.Decode(token, &got), cursor.ErrInvalid, token)
+	}
+}
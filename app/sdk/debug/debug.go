@@ -0,0 +1,67 @@
+// Package debug provides handler support for the debugging endpoints.
+package debug
+
+import (
+	"TODO-list/foundation/logger"
+	"TODO-list/foundation/metrics"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+)
+
+var publish sync.Once
+
+// Mux registers all the debug routes from the standard library into a new
+// mux bypassing the use of the DefaultServerMux. Using the DefaultServerMux
+// would be a security risk since a dependency could inject a handler into
+// our service without us knowing it. These routes are served on a separate
+// port so they are never exposed with the public API.
+func Mux(log *logger.Logger) *http.ServeMux {
+	publish.Do(func() {
+		expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+		metrics.NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.",
+			func() float64 { return float64(runtime.NumGoroutine()) })
+	})
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.Handle("GET /debug/loglevel", logLevel(log))
+	mux.Handle("PUT /debug/loglevel", logLevel(log))
+	mux.Handle("GET /metrics", metrics.Default.Handler())
+
+	return mux
+}
+
+// logLevel reports the minimum level of the log and, on PUT, changes it to
+// the level named by the level query parameter.
+func logLevel(log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			level, err := logger.ParseLevel(r.URL.Query().Get("level"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			old := log.Level()
+			log.SetLevel(level)
+			log.Warn(r.Context(), "debug", "status", "log level changed", "from", old.String(), "to", level.String())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Level string `json:"level"`
+		}{
+			Level: log.Level().String(),
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package debug_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"TODO-list/app/sdk/debug"
+	"TODO-list/foundation/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", nil)
+	mux := debug.Mux(log)
+
+	do := func(method string, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	w := do(http.MethodGet, "/debug/loglevel")
+	assert.JSONEq(t, `{"level":"INFO"}`, w.Body.String())
+
+	log.Debug(context.Background(), "hidden")
+	assert.NotContains(t, buf.String(), "hidden")
+
+	w = do(http.MethodPut, "/debug/loglevel?level=debug")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"DEBUG"}`, w.Body.String())
+
+	log.Debug(context.Background(), "shown")
+	assert.Contains(t, buf.String(), "shown")
+
+	w = do(http.MethodPut, "/debug/loglevel?level=loud")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, logger.LevelDebug, log.Level())
+
+	w = do(http.MethodGet, "/debug/vars")
+	assert.Contains(t, w.Body.String(), `"goroutines"`)
+}
+
+func TestLogLevelWithHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	log := logger.NewWithHandler(h)
+	mux := debug.Mux(log)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/loglevel", nil))
+	assert.JSONEq(t, `{"level":"DEBUG"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/debug/loglevel?level=warn", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	log.Info(context.Background(), "hidden")
+	assert.NotContains(t, buf.String(), "hidden")
+
+	log.Warn(context.Background(), "shown")
+	assert.Contains(t, buf.String(), "shown")
+}
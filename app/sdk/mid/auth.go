@@ -0,0 +1,52 @@
+package mid
+
+import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/foundation/web"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Authenticate validates the bearer token of the Authorization header and
+// puts its claims in the context, where GetUserID finds the caller. The user
+// must still exist and be active, so deactivating a user shuts out the access
+// tokens already issued to them as well.
+func Authenticate(ath *auth.Auth, userBus *userbus.Business) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			claims, err := ath.Authenticate(ctx, r.Header.Get("Authorization"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			userID, err := claims.UserID()
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			usr, err := userBus.QueryById(ctx, userID)
+			if err != nil {
+				if errors.Is(err, userbus.ErrNotFound) {
+					return errs.New(errs.Unauthenticated, err)
+				}
+				return errs.New(errs.InternalOnlyLog, err)
+			}
+
+			if !usr.Active {
+				return errs.New(errs.Unauthenticated, fmt.Errorf("userID[%d]: %w", usr.ID, userbus.ErrUserInactive))
+			}
+
+			ctx = setClaims(ctx, claims)
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,129 @@
+package mid
+
+import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/errs"
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/foundation/web"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// Authorize checks the rule against the claims of the authenticated user. It
+// must run after Authenticate.
+func Authorize(ath *auth.Auth, rule string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if err := ath.Authorize(GetClaims(ctx), rule); err != nil {
+				return errs.New(errs.PermissionDenied, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeUser checks the rule with the user of the {id} path parameter as
+// the subject.
+func AuthorizeUser(ath *auth.Auth, rule string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			id, err := strconv.Atoi(web.Param(r, "id"))
+			if err != nil {
+				return errs.New(errs.InvalidArgument, err)
+			}
+
+			if err := ath.Authorize(GetClaims(ctx), rule, id); err != nil {
+				return errs.New(errs.PermissionDenied, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeProject loads the project of the {id} path parameter and checks
+// the rule with the members holding one of the roles as the subjects.
+func AuthorizeProject(ath *auth.Auth, projectBus *projectbus.Business, rule string, roles ...projectbus.MemberRole) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			id, err := strconv.Atoi(web.Param(r, "id"))
+			if err != nil {
+				return errs.New(errs.InvalidArgument, err)
+			}
+
+			members, err := projectBus.QueryMembers(ctx, id)
+			if err != nil {
+				if errors.Is(err, projectbus.ErrNotFound) {
+					return errs.New(errs.NotFound, err)
+				}
+				return errs.New(errs.InternalOnlyLog, err)
+			}
+
+			var subjects []int
+			for _, mbr := range members {
+				if slices.Contains(roles, mbr.Role) {
+					subjects = append(subjects, mbr.UserID)
+				}
+			}
+
+			if err := ath.Authorize(GetClaims(ctx), rule, subjects...); err != nil {
+				return errs.New(errs.PermissionDenied, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeTask loads the task of the {id} path parameter and checks the
+// rule with its creator and assignee as the subjects.
+func AuthorizeTask(ath *auth.Auth, taskBus *taskbus.Business, rule string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			id, err := strconv.Atoi(web.Param(r, "id"))
+			if err != nil {
+				return errs.New(errs.InvalidArgument, err)
+			}
+
+			task, err := taskBus.QueryByID(ctx, id)
+			if err != nil {
+				if errors.Is(err, taskbus.ErrNotFound) {
+					return errs.New(errs.NotFound, err)
+				}
+				return errs.New(errs.InternalOnlyLog, err)
+			}
+
+			subjects := []int{task.CreatedBy}
+			if task.AssignedTo.Valid {
+				subjects = append(subjects, int(task.AssignedTo.Int32))
+			}
+
+			if err := ath.Authorize(GetClaims(ctx), rule, subjects...); err != nil {
+				return errs.New(errs.PermissionDenied, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,36 @@
+package mid
+
+import (
+	"TODO-list/app/sdk/auth"
+	"context"
+	"errors"
+)
+
+type ctxKey int
+
+const (
+	claimKey ctxKey = iota + 1
+)
+
+func setClaims(ctx context.Context, claims auth.Claims) context.Context {
+	return context.WithValue(ctx, claimKey, claims)
+}
+
+// GetClaims returns the claims from the context.
+func GetClaims(ctx context.Context) auth.Claims {
+	v, ok := ctx.Value(claimKey).(auth.Claims)
+	if !ok {
+		return auth.Claims{}
+	}
+	return v
+}
+
+// GetUserID returns the id of the authenticated user from the context.
+func GetUserID(ctx context.Context) (int, error) {
+	v, ok := ctx.Value(claimKey).(auth.Claims)
+	if !ok {
+		return 0, errors.New("user id not found in context")
+	}
+
+	return v.UserID()
+}
@@ -0,0 +1,54 @@
+package mid
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/foundation/logger"
+	"TODO-list/foundation/web"
+	"context"
+	"errors"
+	"net/http"
+	"path"
+)
+
+// internalMessage replaces the message of internal errors in responses.
+const internalMessage = "internal server error"
+
+// Errors handles errors coming out of the call chain. Every error is logged
+// along with where it was constructed. Internal errors only make sense to us,
+// so their message is replaced before the response goes to the client.
+func Errors(log *logger.Logger) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			resp := next(ctx, r)
+			err := isError(resp)
+			if err == nil {
+				return resp
+			}
+
+			var appErr *errs.Error
+			switch {
+			case errors.As(err, &appErr):
+			case errs.IsFieldErrors(err):
+				appErr = errs.New(errs.InvalidArgument, err)
+			default:
+				appErr = errs.New(errs.Internal, err)
+			}
+
+			log.Error(ctx, "handled error during request",
+				"err", err,
+				"source_err_file", path.Base(appErr.FileName),
+				"source_err_func", path.Base(appErr.FuncName))
+
+			switch appErr.Code {
+			case errs.Internal, errs.InternalOnlyLog:
+				return errs.Newf(errs.Internal, internalMessage)
+			}
+
+			return appErr
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,41 @@
+package mid
+
+import (
+	"TODO-list/foundation/metrics"
+	"TODO-list/foundation/web"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	requests = metrics.NewCounter("http_requests_total",
+		"Number of requests handled by route and status code.", "route", "code")
+
+	requestDuration = metrics.NewHistogram("http_request_duration_seconds",
+		"Latency of the requests by route.", metrics.DefBuckets, "route")
+)
+
+// Metrics records the number of requests and their latency per route. The
+// route pattern is used as label, not the path, so ids do not create a new
+// series for every request.
+func Metrics() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			now := time.Now()
+
+			resp := next(ctx, r)
+
+			route := web.GetRoute(ctx)
+			requests.Inc(route, strconv.Itoa(statusCode(resp)))
+			requestDuration.Observe(time.Since(now).Seconds(), route)
+
+			return resp
+		}
+
+		return h
+	}
+
+	return m
+}
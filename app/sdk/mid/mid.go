@@ -0,0 +1,35 @@
+// Package mid provides app level middleware support.
+package mid
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/foundation/web"
+	"errors"
+	"net/http"
+)
+
+// isError tests if the Encoder has an error inside of it.
+func isError(e web.Encoder) error {
+	err, isError := e.(error)
+	if isError {
+		return err
+	}
+	return nil
+}
+
+// statusCode returns the HTTP status web.Respond will send for the response.
+func statusCode(resp web.Encoder) int {
+	if err := isError(resp); err != nil {
+		var appErr *errs.Error
+		if errors.As(err, &appErr) {
+			return appErr.HTTPStatus()
+		}
+		return http.StatusInternalServerError
+	}
+
+	if resp == nil {
+		return http.StatusNoContent
+	}
+
+	return http.StatusOK
+}
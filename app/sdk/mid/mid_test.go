@@ -0,0 +1,162 @@
+package mid_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"TODO-list/app/sdk/errs"
+	"TODO-list/app/sdk/mid"
+	"TODO-list/foundation/logger"
+	"TODO-list/foundation/otel"
+	"TODO-list/foundation/web"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newApp(t *testing.T, handler web.HandlerFunc) (*web.App, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", func(context.Context) string { return "" })
+
+	app := web.NewApp(
+		func(ctx context.Context, msg string, args ...any) { log.Info(ctx, msg, args...) },
+		mid.Logger(log),
+		mid.Errors(log),
+		mid.Panics(),
+	)
+	app.HandlerFunc(http.MethodGet, "", "/test", handler)
+
+	return app, &buf
+}
+
+func serve(app *web.App) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	return w
+}
+
+func TestErrors(t *testing.T) {
+	tests := map[string]struct {
+		resp   web.Encoder
+		status int
+		body   string
+		logged string
+	}{
+		"not found": {
+			resp:   errs.Newf(errs.NotFound, "task not found"),
+			status: http.StatusNotFound,
+			body:   "task not found",
+			logged: "task not found",
+		},
+		"internal only log": {
+			resp:   errs.Newf(errs.InternalOnlyLog, "table task is missing"),
+			status: http.StatusInternalServerError,
+			body:   "internal server error",
+			logged: "table task is missing",
+		},
+		"panic message": {
+			resp:   errs.Newf(errs.Internal, "PANIC [boom]"),
+			status: http.StatusInternalServerError,
+			body:   "internal server error",
+			logged: "PANIC [boom]",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			app, buf := newApp(t, func(ctx context.Context, r *http.Request) web.Encoder {
+				return tt.resp
+			})
+
+			w := serve(app)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+			assert.Contains(t, buf.String(), "handled error during request")
+			assert.Contains(t, buf.String(), tt.logged)
+			assert.Contains(t, buf.String(), "mid_test.go")
+		})
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	app, _ := newApp(t, func(ctx context.Context, r *http.Request) web.Encoder {
+		return errs.NewFieldsError("title", errors.New("required"))
+	})
+
+	w := serve(app)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "required")
+}
+
+func TestPanics(t *testing.T) {
+	app, buf := newApp(t, func(ctx context.Context, r *http.Request) web.Encoder {
+		panic(errors.New("boom"))
+	})
+
+	w := serve(app)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "boom")
+	assert.Contains(t, buf.String(), "PANIC [boom]")
+}
+
+func TestLogger(t *testing.T) {
+	app, buf := newApp(t, func(ctx context.Context, r *http.Request) web.Encoder {
+		return nil
+	})
+
+	w := serve(app)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, buf.String(), "request started")
+	assert.Contains(t, buf.String(), `"statuscode":204`)
+	assert.Contains(t, buf.String(), `"since"`)
+}
+
+func TestOtel(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", otel.GetTraceID)
+
+	app := web.NewApp(
+		func(ctx context.Context, msg string, args ...any) { log.Info(ctx, msg, args...) },
+		mid.Otel(provider.Tracer("TEST")),
+		mid.Logger(log),
+	)
+	app.HandlerFunc(http.MethodGet, "", "/test", func(ctx context.Context, r *http.Request) web.Encoder {
+		return nil
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	r := httptest.NewRequest(http.MethodGet, "/test", nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	assert.Contains(t, w.Header().Get("traceparent"), traceID)
+	assert.Contains(t, buf.String(), `"trace_id":"`+traceID+`"`)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GET /test", spans[0].Name)
+		assert.Equal(t, traceID, spans[0].SpanContext.TraceID().String())
+	}
+
+	w = serve(app)
+
+	traceparent := w.Header().Get("traceparent")
+	assert.Regexp(t, "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$", traceparent)
+	assert.NotContains(t, traceparent, traceID)
+}
@@ -0,0 +1,56 @@
+package mid
+
+import (
+	"TODO-list/foundation/otel"
+	"TODO-list/foundation/web"
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Otel starts a span for every request. The trace continues the one named by
+// the traceparent header of the request when present, and the traceparent of
+// the span is echoed back in the response so callers can find the trace.
+func Otel(tracer trace.Tracer) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			ctx = otel.ExtractTraceContext(ctx, r)
+
+			spanName := web.GetRoute(ctx)
+			if spanName == "" {
+				spanName = fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+			}
+
+			ctx, span := tracer.Start(ctx, spanName,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("url.path", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			ctx = otel.InjectTracing(ctx, tracer)
+
+			if w := web.GetWriter(ctx); w != nil {
+				otel.AddTraceToHeader(ctx, w.Header())
+			}
+
+			resp := next(ctx, r)
+
+			if err := isError(resp); err != nil {
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return resp
+		}
+
+		return h
+	}
+
+	return m
+}
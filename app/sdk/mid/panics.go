@@ -0,0 +1,33 @@
+package mid
+
+import (
+	"TODO-list/app/sdk/errs"
+	"TODO-list/foundation/web"
+	"context"
+	"net/http"
+	"runtime/debug"
+)
+
+// Panics recovers from panics and converts the panic to an error so it is
+// reported in Errors and handled appropriately.
+func Panics() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) (resp web.Encoder) {
+
+			// Defer a function to recover from a panic and set the resp
+			// return variable after the fact.
+			defer func() {
+				if rec := recover(); rec != nil {
+					trace := debug.Stack()
+					resp = errs.Newf(errs.Internal, "PANIC [%v] TRACE[%s]", rec, string(trace))
+				}
+			}()
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -1,23 +1,63 @@
 package mux
 
 import (
+	"TODO-list/app/domain/authapp"
+	"TODO-list/app/domain/checkapp"
 	"TODO-list/app/domain/projectapp"
 	"TODO-list/app/domain/taskapp"
 	"TODO-list/app/domain/userapp"
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/cursor"
+	"TODO-list/app/sdk/mid"
 	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/projectbus/stores/projectdb"
+	"TODO-list/business/domain/projectbus/stores/projectmem"
 	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/taskbus/stores/taskdb"
+	"TODO-list/business/domain/taskbus/stores/taskmem"
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/tokenbus/stores/tokendb"
+	"TODO-list/business/domain/tokenbus/stores/tokenmem"
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/userdb"
+	"TODO-list/business/domain/userbus/stores/usermem"
+	"TODO-list/business/sdk/memdb"
 	"TODO-list/foundation/logger"
 	"TODO-list/foundation/web"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // Config holds the dependencies required for initializing the web API.
 type Config struct {
-	Log *logger.Logger
-	DB  *sql.DB
+	Build  string
+	Log    *logger.Logger
+	Tracer trace.Tracer
+	DB     *sql.DB
+	Auth   *auth.Auth
+
+	// MemDB backs the stores with an in-memory database instead of DB when
+	// it is set.
+	MemDB *memdb.DB
+
+	// CursorKey signs the cursors of the keyset listings. A random key is
+	// used when it is empty.
+	CursorKey []byte
+
+	// AuthKID names the key signing the access tokens handed out on login.
+	AuthKID string
+
+	// AccessTTL and RefreshTTL are the lifetimes of the access and refresh
+	// tokens. They default to 15 minutes and 30 days.
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
 }
 
 // WebAPI initializes the web application with the given configuration.
@@ -25,25 +65,98 @@ func WebAPI(cfg Config) (http.Handler, error) {
 	logger := func(ctx context.Context, msg string, args ...any) {
 		cfg.Log.Info(ctx, msg, args...)
 	}
-	app := web.NewApp(logger)
+	tracer := cfg.Tracer
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("")
+	}
 
-	userBus := userbus.NewBusiness(cfg.DB)
-	projectBus := projectbus.NewBusiness(cfg.DB, userBus)
-	taskBus := taskbus.NewBusiness(cfg.DB, userBus, projectBus)
+	if cfg.Auth == nil {
+		return nil, errors.New("auth is required")
+	}
+	if cfg.AuthKID == "" {
+		return nil, errors.New("auth kid is required")
+	}
+
+	accessTTL := cfg.AccessTTL
+	if accessTTL == 0 {
+		accessTTL = 15 * time.Minute
+	}
+	refreshTTL := cfg.RefreshTTL
+	if refreshTTL == 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
+
+	cursorKey := cfg.CursorKey
+	if len(cursorKey) == 0 {
+		var err error
+		if cursorKey, err = cursor.NewKey(); err != nil {
+			return nil, fmt.Errorf("cursor key: %w", err)
+		}
+	}
+
+	app := web.NewApp(
+		logger,
+		mid.Otel(tracer),
+		mid.Logger(cfg.Log),
+		mid.Metrics(),
+		mid.Errors(cfg.Log),
+		mid.Panics(),
+	)
+
+	var (
+		userStorer    userbus.Storer    = userdb.NewStore(cfg.DB)
+		projectStorer projectbus.Storer = projectdb.NewStore(cfg.DB)
+		taskStorer    taskbus.Storer    = taskdb.NewStore(cfg.DB)
+		tokenStorer   tokenbus.Storer   = tokendb.NewStore(cfg.DB)
+	)
+
+	if cfg.MemDB != nil {
+		userStorer = usermem.NewStore(cfg.MemDB)
+		projectStorer = projectmem.NewStore(cfg.MemDB)
+		taskStorer = taskmem.NewStore(cfg.MemDB)
+		tokenStorer = tokenmem.NewStore(cfg.MemDB)
+	}
+
+	userBus := userbus.NewBusiness(userStorer)
+	projectBus := projectbus.NewBusiness(projectStorer, userBus)
+	taskBus := taskbus.NewBusiness(taskStorer, userBus, projectBus)
+	tokenBus := tokenbus.NewBusiness(tokenStorer, userBus, refreshTTL)
+
+	checkapp.Routes(app, checkapp.Config{
+		Build:  cfg.Build,
+		Logger: cfg.Log,
+		DB:     cfg.DB,
+	})
+
+	authapp.Routes(app, authapp.Config{
+		UserBus:   userBus,
+		TokenBus:  tokenBus,
+		Logger:    cfg.Log,
+		Auth:      cfg.Auth,
+		KID:       cfg.AuthKID,
+		AccessTTL: accessTTL,
+	})
 
 	userapp.Routes(app, userapp.Config{
-		UserBus: userBus,
-		Logger:  cfg.Log,
+		UserBus:  userBus,
+		TokenBus: tokenBus,
+		Logger:   cfg.Log,
+		Auth:     cfg.Auth,
 	})
 
 	taskapp.Routes(app, taskapp.Config{
+		UserBus: userBus,
 		TaskBus: taskBus,
 		Logger:  cfg.Log,
+		Cursors: cursor.NewSigner(cursorKey),
+		Auth:    cfg.Auth,
 	})
 
 	projectapp.Routes(app, projectapp.Config{
+		UserBus:    userBus,
 		ProjectBus: projectBus,
 		Logger:     cfg.Log,
+		Auth:       cfg.Auth,
 	})
 
 	return app, nil
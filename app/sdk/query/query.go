@@ -0,0 +1,62 @@
+// Package query provides support for query paging.
+package query
+
+import (
+	"TODO-list/business/sdk/page"
+	"encoding/json"
+)
+
+// Result is the data model used when returning a query result.
+type Result[T any] struct {
+	Items       []T `json:"items"`
+	Total       int `json:"total"`
+	Page        int `json:"page"`
+	RowsPerPage int `json:"rowsPerPage"`
+}
+
+// NewResult constructs a result value to return query results.
+func NewResult[T any](items []T, total int, page page.Page) Result[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return Result[T]{
+		Items:       items,
+		Total:       total,
+		Page:        page.Number(),
+		RowsPerPage: page.RowsPerPage(),
+	}
+}
+
+// Encode implements the encoder interface.
+func (r Result[T]) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(r)
+	return data, "application/json", err
+}
+
+// CursorResult is the data model used when returning a keyset query result.
+// NextCursor is empty on the last page.
+type CursorResult[T any] struct {
+	Items       []T    `json:"items"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	RowsPerPage int    `json:"rowsPerPage"`
+}
+
+// NewCursorResult constructs a result value to return keyset query results.
+func NewCursorResult[T any](items []T, nextCursor string, rowsPerPage int) CursorResult[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return CursorResult[T]{
+		Items:       items,
+		NextCursor:  nextCursor,
+		RowsPerPage: rowsPerPage,
+	}
+}
+
+// Encode implements the encoder interface.
+func (r CursorResult[T]) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(r)
+	return data, "application/json", err
+}
@@ -0,0 +1,9 @@
+package projectbus
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field is not filtered on.
+type QueryFilter struct {
+	Active    *bool
+	CreatedBy *int
+	Name      *string
+}
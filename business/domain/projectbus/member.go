@@ -0,0 +1,81 @@
+package projectbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// The set of roles a member can have in a project.
+var (
+	MemberRoleOwner      = MemberRole{"owner"}
+	MemberRoleMaintainer = MemberRole{"maintainer"}
+	MemberRoleMember     = MemberRole{"member"}
+	MemberRoleViewer     = MemberRole{"viewer"}
+)
+
+var memberRoles = map[string]MemberRole{
+	MemberRoleOwner.name:      MemberRoleOwner,
+	MemberRoleMaintainer.name: MemberRoleMaintainer,
+	MemberRoleMember.name:     MemberRoleMember,
+	MemberRoleViewer.name:     MemberRoleViewer,
+}
+
+// MemberRole represents what a member may do in a project. Owners and
+// maintainers manage the members, though only owners grant or remove the
+// owner role, and owners alone delete or deactivate the project. Members edit
+// the project and viewers only read it. Tasks are edited by their creator or
+// assignee whatever their role.
+type MemberRole struct {
+	name string
+}
+
+// ParseMemberRole parses the string value and returns a member role if one
+// exists.
+func ParseMemberRole(value string) (MemberRole, error) {
+	role, exists := memberRoles[value]
+	if !exists {
+		return MemberRole{}, fmt.Errorf("invalid member role %q", value)
+	}
+
+	return role, nil
+}
+
+// MustParseMemberRole parses the string value and returns a member role if
+// one exists. If an error occurs the function panics.
+func MustParseMemberRole(value string) MemberRole {
+	role, err := ParseMemberRole(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return role
+}
+
+// String returns the name of the member role.
+func (r MemberRole) String() string {
+	return r.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r MemberRole) Equal(r2 MemberRole) bool {
+	return r.name == r2.name
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r MemberRole) MarshalText() ([]byte, error) {
+	return []byte(r.name), nil
+}
+
+// Member represents a user taking part in a project.
+type Member struct {
+	ProjectID int        `json:"project_id"`
+	UserID    int        `json:"user_id"`
+	Role      MemberRole `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewMember represents the data required to add a member to a project.
+type NewMember struct {
+	UserID int
+	Role   MemberRole
+}
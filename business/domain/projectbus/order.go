@@ -0,0 +1,14 @@
+package projectbus
+
+import "TODO-list/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID        = "id"
+	OrderByName      = "name"
+	OrderByCreatedAt = "created_at"
+	OrderByCreatedBy = "created_by"
+)
@@ -2,134 +2,273 @@ package projectbus
 
 import (
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"TODO-list/foundation/metrics"
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound        = errors.New("project not found")
+	ErrProjectInactive = errors.New("project is not active")
+	ErrMemberNotFound  = errors.New("project member not found")
+	ErrMemberExists    = errors.New("user is already a project member")
+	ErrLastOwner       = errors.New("project must keep an owner")
+)
+
+var projectsDeactivated = metrics.NewCounter("projects_deactivated_total", "Number of projects deactivated.")
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, prj Project, owner Member) (int, error)
+	Update(ctx context.Context, prj Project) error
+	Delete(ctx context.Context, id int) error
+	HasTasks(ctx context.Context, id int) (bool, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy []order.By, page page.Page) ([]Project, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, id int) (Project, error)
+	AddMember(ctx context.Context, mbr Member) error
+	RemoveMember(ctx context.Context, projectID int, userID int) error
+	QueryMembers(ctx context.Context, projectID int) ([]Member, error)
+	QueryMember(ctx context.Context, projectID int, userID int) (Member, error)
+}
+
 // Business handles business logic and persistence for project-related operations.
 type Business struct {
-	db      *sql.DB
+	storer  Storer
 	userBus *userbus.Business
 }
 
-// NewBusiness creates a new instance of Business with the provided database connection and user operations.
-func NewBusiness(db *sql.DB, userBus *userbus.Business) *Business {
+// NewBusiness creates a new instance of Business with the provided storer and user operations.
+func NewBusiness(storer Storer, userBus *userbus.Business) *Business {
 	return &Business{
-		db:      db,
+		storer:  storer,
 		userBus: userBus,
 	}
 }
 
-// Create inserts a new project into the database and returns the created project.
+// Create inserts a new project into the database and returns the created
+// project. The creator joins it as its owner in the same transaction.
 func (s *Business) Create(ctx context.Context, np NewProject) (Project, error) {
 	creator, err := s.userBus.QueryById(ctx, np.CreatedBy)
 	if err != nil {
-		return Project{}, fmt.Errorf("failed to retrieve creator user with ID %d: %v", np.CreatedBy, err)
+		return Project{}, fmt.Errorf("failed to retrieve creator user with ID %d: %w", np.CreatedBy, err)
 	}
 	if !creator.Active {
-		return Project{}, fmt.Errorf("creator user with ID %d is not active", np.CreatedBy)
+		return Project{}, fmt.Errorf("creator userID[%d]: %w", np.CreatedBy, userbus.ErrUserInactive)
 	}
 
-	createdAt := time.Now()
-	query := "INSERT INTO project (name, active, created_at, created_by) VALUES (?, ?, ?, ?) "
-	result, err := s.db.ExecContext(ctx, query, np.Name, true, createdAt, np.CreatedBy)
-	if err != nil {
-		return Project{}, err
+	prj := Project{
+		Name:      np.Name,
+		Active:    true,
+		CreatedAt: nowUTC(),
+		CreatedBy: np.CreatedBy,
+	}
+
+	owner := Member{
+		UserID:    prj.CreatedBy,
+		Role:      MemberRoleOwner,
+		CreatedAt: prj.CreatedAt,
 	}
 
-	lastInsertID, err := result.LastInsertId()
+	id, err := s.storer.Create(ctx, prj, owner)
 	if err != nil {
-		return Project{}, err
+		return Project{}, fmt.Errorf("create: %w", err)
 	}
+	prj.ID = id
 
-	return Project{
-		ID:        int(lastInsertID),
-		Name:      np.Name,
-		Active:    true,
-		CreatedAt: createdAt,
-		CreatedBy: np.CreatedBy,
-	}, nil
+	return prj, nil
 }
 
-// Query retrieves all projects from the database.
-func (s *Business) Query(ctx context.Context) ([]Project, error) {
-	query := "SELECT id, name, active, created_at, created_by FROM project"
-	rows, err := s.db.QueryContext(ctx, query)
+// Query retrieves a page of the projects matching the filter in the given order.
+func (s *Business) Query(ctx context.Context, filter QueryFilter, orderBy []order.By, page page.Page) ([]Project, error) {
+	projects, err := s.storer.Query(ctx, filter, orderBy, page)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("query: %w", err)
 	}
-	defer rows.Close()
 
-	var projects []Project
-	for rows.Next() {
-		var project Project
-		err := rows.Scan(&project.ID, &project.Name, &project.Active, &project.CreatedAt, &project.CreatedBy)
-		if err != nil {
-			return nil, err
-		}
-		projects = append(projects, project)
-	}
+	return projects, nil
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+// Count returns the total number of projects matching the filter.
+func (s *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	n, err := s.storer.Count(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("count: %w", err)
 	}
 
-	return projects, nil
+	return n, nil
 }
 
 // QueryById retrieves a specific project by its ID from the database.
 func (s *Business) QueryById(ctx context.Context, id int) (Project, error) {
-	query := "SELECT id, name, active, created_at, created_by FROM project WHERE id = ?"
-	row := s.db.QueryRowContext(ctx, query, id)
-
-	var project Project
-	err := row.Scan(&project.ID, &project.Name, &project.Active, &project.CreatedAt, &project.CreatedBy)
+	prj, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return Project{}, err
+		return Project{}, fmt.Errorf("query: projectID[%d]: %w", id, err)
 	}
 
-	return project, nil
+	return prj, nil
 }
 
 // Update modifies an existing project's information in the database.
 func (s *Business) Update(ctx context.Context, id int, up UpdateProject) error {
-	query := "UPDATE project SET name = ? WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, up.Name, id)
+	prj, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("query: projectID[%d]: %w", id, err)
+	}
+
+	prj.Name = up.Name
+
+	if err := s.storer.Update(ctx, prj); err != nil {
+		return fmt.Errorf("update: %w", err)
 	}
 
 	return nil
 }
 
-// Delete removes a project from the database by its ID.
+// Delete removes a project from the database by its ID. Projects that still
+// have tasks are deactivated instead.
 func (s *Business) Delete(ctx context.Context, id int) error {
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM task WHERE project_id = ?)"
-	var hasTasks bool
-	err := s.db.QueryRowContext(ctx, checkQuery, id).Scan(&hasTasks)
+	hasTasks, err := s.storer.HasTasks(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to check if project has associated tasks with ID %d: %w", id, err)
 	}
 	if hasTasks {
 		return s.Deactivate(ctx, id)
 	}
-	deleteQuery := "DELETE FROM project WHERE id = ?"
-	_, err = s.db.ExecContext(ctx, deleteQuery, id)
-	if err != nil {
+
+	if err := s.storer.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete project with ID %d: %w", id, err)
 	}
 
 	return nil
 }
 
+// AddMember adds an active user to an active project. Users already in the
+// project fail with ErrMemberExists.
+func (s *Business) AddMember(ctx context.Context, projectID int, nm NewMember) (Member, error) {
+	prj, err := s.storer.QueryByID(ctx, projectID)
+	if err != nil {
+		return Member{}, fmt.Errorf("query: projectID[%d]: %w", projectID, err)
+	}
+	if !prj.Active {
+		return Member{}, fmt.Errorf("projectID[%d]: %w", projectID, ErrProjectInactive)
+	}
+
+	usr, err := s.userBus.QueryById(ctx, nm.UserID)
+	if err != nil {
+		return Member{}, err
+	}
+	if !usr.Active {
+		return Member{}, fmt.Errorf("userID[%d]: %w", nm.UserID, userbus.ErrUserInactive)
+	}
+
+	mbr := Member{
+		ProjectID: projectID,
+		UserID:    nm.UserID,
+		Role:      nm.Role,
+		CreatedAt: nowUTC(),
+	}
+
+	if err := s.storer.AddMember(ctx, mbr); err != nil {
+		return Member{}, fmt.Errorf("add member: %w", err)
+	}
+
+	return mbr, nil
+}
+
+// RemoveMember removes a user from a project. The last owner can not be
+// removed.
+func (s *Business) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	mbr, err := s.storer.QueryMember(ctx, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("query: projectID[%d] userID[%d]: %w", projectID, userID, err)
+	}
+
+	if mbr.Role == MemberRoleOwner {
+		if err := s.checkOwners(ctx, projectID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.storer.RemoveMember(ctx, projectID, userID); err != nil {
+		return fmt.Errorf("remove member: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMembers retrieves the members of a project in the order they joined.
+func (s *Business) QueryMembers(ctx context.Context, projectID int) ([]Member, error) {
+	if _, err := s.storer.QueryByID(ctx, projectID); err != nil {
+		return nil, fmt.Errorf("query: projectID[%d]: %w", projectID, err)
+	}
+
+	members, err := s.storer.QueryMembers(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query members: projectID[%d]: %w", projectID, err)
+	}
+
+	return members, nil
+}
+
+// QueryMember retrieves the membership of a user in a project.
+func (s *Business) QueryMember(ctx context.Context, projectID int, userID int) (Member, error) {
+	mbr, err := s.storer.QueryMember(ctx, projectID, userID)
+	if err != nil {
+		return Member{}, fmt.Errorf("query: projectID[%d] userID[%d]: %w", projectID, userID, err)
+	}
+
+	return mbr, nil
+}
+
+// checkOwners fails with ErrLastOwner unless the project has another owner
+// left after one is removed.
+func (s *Business) checkOwners(ctx context.Context, projectID int) error {
+	members, err := s.storer.QueryMembers(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("query members: projectID[%d]: %w", projectID, err)
+	}
+
+	var owners int
+	for _, mbr := range members {
+		if mbr.Role == MemberRoleOwner {
+			owners++
+		}
+	}
+
+	if owners <= 1 {
+		return fmt.Errorf("projectID[%d]: %w", projectID, ErrLastOwner)
+	}
+
+	return nil
+}
+
 // Deactivate sets a project's status to inactive (false) in the database.
 func (s *Business) Deactivate(ctx context.Context, id int) error {
-	query := "UPDATE project SET active = false WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, id)
+	prj, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to deactivate project with ID %d: %v", id, err)
+		return fmt.Errorf("query: projectID[%d]: %w", id, err)
+	}
+
+	prj.Active = false
+
+	if err := s.storer.Update(ctx, prj); err != nil {
+		return fmt.Errorf("failed to deactivate project with ID %d: %w", id, err)
 	}
+
+	projectsDeactivated.Inc()
+
 	return nil
 }
+
+// nowUTC returns the current time in UTC to the second, the way the user and
+// task times are kept, so every stored date shares one offset.
+func nowUTC() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
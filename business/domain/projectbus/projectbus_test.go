@@ -2,52 +2,38 @@ package projectbus_test
 
 import (
 	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/projectbus/stores/projectmem"
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/taskbus/stores/taskmem"
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/usermem"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 	"context"
-	"database/sql"
 	"testing"
-	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
-var (
-	db       *sql.DB
-	mock     sqlmock.Sqlmock
-	business *projectbus.Business
-)
+// setup seeds an active and an inactive user and returns the business along
+// with the database backing it.
+func setup(t *testing.T) (*projectbus.Business, *memdb.DB) {
+	db := memdb.New()
+	userBus := userbus.NewBusiness(usermem.NewStore(db))
 
-func setupMockDB(t *testing.T) {
-	var err error
-	db, mock, err = sqlmock.New()
+	ctx := context.Background()
+	_, err := userBus.Create(ctx, userbus.NewUser{Name: "Creator Name", Email: "creator@example.com"})
 	assert.NoError(t, err)
+	inactive, err := userBus.Create(ctx, userbus.NewUser{Name: "Inactive Name", Email: "inactive@example.com"})
+	assert.NoError(t, err)
+	assert.NoError(t, userBus.Deactivate(ctx, inactive.ID))
 
-	userBus := userbus.NewBusiness(db)
-	business = projectbus.NewBusiness(db, userBus)
-}
-
-func mockProjectRows() *sqlmock.Rows {
-	return sqlmock.NewRows([]string{"id", "name", "active", "created_at", "created_by"}).
-		AddRow(1, "Project 1", true, time.Now(), 1).
-		AddRow(2, "Project 2", false, time.Now(), 1)
+	return projectbus.NewBusiness(projectmem.NewStore(db), userBus), db
 }
 
-func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
 func TestCreate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
-
-	mock.ExpectQuery("SELECT id, name, email, active, created_at, updated_at FROM users WHERE id = ?").
-		WithArgs(int64(1)).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "active", "created_at", "updated_at"}).
-			AddRow(1, "Creator Name", "creator@example.com", true, time.Now(), time.Now()))
-
-	mock.ExpectExec("INSERT INTO project \\(name, active, created_at, created_by\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
-		WithArgs("New Project", true, sqlmock.AnyArg(), 1).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	business, _ := setup(t)
 
 	ctx := context.Background()
 	newProject := projectbus.NewProject{
@@ -61,114 +47,130 @@ func TestCreate(t *testing.T) {
 	assert.Equal(t, "New Project", project.Name)
 	assert.True(t, project.Active)
 	assert.NotEmpty(t, project.CreatedAt)
-	assert.Equal(t, int64(1), int64(project.ID))
-
-	assertMockExpectations(t, mock)
+	assert.Equal(t, 1, project.CreatedBy)
 }
 
-func TestQuery(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestCreateInactiveCreator(t *testing.T) {
+	business, _ := setup(t)
 
-	mock.ExpectQuery("SELECT id, name, active, created_at, created_by FROM project").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "active", "created_at", "created_by"}).
-			AddRow(1, "Project 1", true, time.Now(), 1).
-			AddRow(2, "Project 2", true, time.Now(), 1))
+	_, err := business.Create(context.Background(), projectbus.NewProject{Name: "New Project", CreatedBy: 2})
+	assert.ErrorIs(t, err, userbus.ErrUserInactive)
+}
+
+func TestUpdate(t *testing.T) {
+	business, _ := setup(t)
 
 	ctx := context.Background()
-	projects, err := business.Query(ctx)
+	project, err := business.Create(ctx, projectbus.NewProject{Name: "Project", CreatedBy: 1})
+	assert.NoError(t, err)
 
+	err = business.Update(ctx, project.ID, projectbus.UpdateProject{Name: "Updated Project"})
 	assert.NoError(t, err)
-	assert.Len(t, projects, 2)
-	assert.Equal(t, "Project 1", projects[0].Name)
-	assert.True(t, projects[0].Active)
-	assert.NotEmpty(t, projects[0].CreatedAt)
-	assert.True(t, projects[0].CreatedAt.After(time.Now().Add(-time.Hour)))
-	assert.Equal(t, 1, projects[0].CreatedBy)
-	assertMockExpectations(t, mock)
-}
 
-func TestQueryById(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+	project, err = business.QueryById(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated Project", project.Name)
+	assert.True(t, project.Active)
+}
 
-	mock.ExpectQuery("SELECT id, name, active, created_at, created_by FROM project WHERE id = ?").
-		WithArgs(3).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "active", "created_at", "created_by"}).
-			AddRow(3, "Project 3", true, time.Now(), 3))
+func TestDelete(t *testing.T) {
+	business, db := setup(t)
 
 	ctx := context.Background()
-	project, err := business.QueryById(ctx, 3)
-
+	empty, err := business.Create(ctx, projectbus.NewProject{Name: "Empty", CreatedBy: 1})
+	assert.NoError(t, err)
+	busy, err := business.Create(ctx, projectbus.NewProject{Name: "Busy", CreatedBy: 1})
 	assert.NoError(t, err)
-	assert.Equal(t, "Project 3", project.Name)
-	assert.True(t, project.Active)
-	assert.NotEmpty(t, project.CreatedAt)
 
-	assertMockExpectations(t, mock)
-}
+	taskmem.NewStore(db).Create(ctx, taskbus.Task{Title: "Task", ProjectID: busy.ID, CreatedBy: 1})
 
-func TestUpdate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+	assert.NoError(t, business.Delete(ctx, empty.ID))
+	_, err = business.QueryById(ctx, empty.ID)
+	assert.ErrorIs(t, err, projectbus.ErrNotFound)
+
+	assert.NoError(t, business.Delete(ctx, busy.ID))
+	busy, err = business.QueryById(ctx, busy.ID)
+	assert.NoError(t, err)
+	assert.False(t, busy.Active)
+}
 
-	mock.ExpectExec("^UPDATE project SET name = \\? WHERE id = \\?$").
-		WithArgs("Updated Project", 1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+func TestQuery(t *testing.T) {
+	business, _ := setup(t)
 
 	ctx := context.Background()
+	for _, name := range []string{"Home", "Work", "Home office"} {
+		_, err := business.Create(ctx, projectbus.NewProject{Name: name, CreatedBy: 1})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, business.Deactivate(ctx, 2))
 
-	update := projectbus.UpdateProject{
-		Name: "Updated Project",
+	name := "home"
+	projects, err := business.Query(ctx, projectbus.QueryFilter{Name: &name}, []order.By{order.NewBy(projectbus.OrderByName, order.DESC)}, page.MustParse("1", "10"))
+	assert.NoError(t, err)
+	if assert.Len(t, projects, 2) {
+		assert.Equal(t, "Home office", projects[0].Name)
+		assert.Equal(t, "Home", projects[1].Name)
 	}
-	err := business.Update(ctx, 1, update)
 
+	active := true
+	createdBy := 1
+	filter := projectbus.QueryFilter{Active: &active, CreatedBy: &createdBy}
+	projects, err = business.Query(ctx, filter, []order.By{projectbus.DefaultOrderBy}, page.MustParse("1", "10"))
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
-}
+	if assert.Len(t, projects, 2) {
+		assert.Equal(t, 1, projects[0].ID)
+		assert.Equal(t, 3, projects[1].ID)
+	}
 
-func TestDeactivate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+	count, err := business.Count(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
 
-	mock.ExpectExec("^UPDATE project SET active = false WHERE id = \\?$").
-		WithArgs(1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+func TestMembers(t *testing.T) {
+	business, db := setup(t)
 
 	ctx := context.Background()
-	err := business.Deactivate(ctx, 1)
+	member, err := userbus.NewBusiness(usermem.NewStore(db)).Create(ctx, userbus.NewUser{Name: "Member Name", Email: "member@example.com"})
+	assert.NoError(t, err)
 
+	project, err := business.Create(ctx, projectbus.NewProject{Name: "Project", CreatedBy: 1})
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
-}
 
-func TestDelete(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+	owner, err := business.QueryMember(ctx, project.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, projectbus.MemberRoleOwner, owner.Role, "the creator owns the project")
 
-	mock.ExpectQuery("^SELECT EXISTS\\(SELECT 1 FROM task WHERE project_id = \\?\\)$").
-		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	_, err = business.AddMember(ctx, project.ID, projectbus.NewMember{UserID: member.ID, Role: projectbus.MemberRoleViewer})
+	assert.NoError(t, err)
 
-	mock.ExpectExec("^DELETE FROM project WHERE id = \\?$").
-		WithArgs(1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = business.AddMember(ctx, project.ID, projectbus.NewMember{UserID: member.ID, Role: projectbus.MemberRoleMember})
+	assert.ErrorIs(t, err, projectbus.ErrMemberExists)
 
-	ctx := context.Background()
-	err := business.Delete(ctx, 1)
+	_, err = business.AddMember(ctx, project.ID, projectbus.NewMember{UserID: 2, Role: projectbus.MemberRoleMember})
+	assert.ErrorIs(t, err, userbus.ErrUserInactive)
 
-	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
+	_, err = business.AddMember(ctx, 9, projectbus.NewMember{UserID: member.ID, Role: projectbus.MemberRoleMember})
+	assert.ErrorIs(t, err, projectbus.ErrNotFound)
 
-	mock.ExpectQuery("^SELECT EXISTS\\(SELECT 1 FROM task WHERE project_id = \\?\\)$").
-		WithArgs(2).
-		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
-	mock.ExpectExec("^UPDATE project SET active = false WHERE id = \\?$").
-		WithArgs(2).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.ErrorIs(t, business.RemoveMember(ctx, project.ID, 1), projectbus.ErrLastOwner)
 
-	err = business.Delete(ctx, 2)
+	// Ownership moves by adding another owner before the creator leaves.
+	assert.NoError(t, business.RemoveMember(ctx, project.ID, member.ID))
+	_, err = business.AddMember(ctx, project.ID, projectbus.NewMember{UserID: member.ID, Role: projectbus.MemberRoleOwner})
+	assert.NoError(t, err)
+	assert.NoError(t, business.RemoveMember(ctx, project.ID, 1))
 
+	members, err := business.QueryMembers(ctx, project.ID)
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, member.ID, members[0].UserID)
+		assert.Equal(t, projectbus.MemberRoleOwner, members[0].Role)
+	}
+
+	assert.ErrorIs(t, business.RemoveMember(ctx, project.ID, 1), projectbus.ErrMemberNotFound)
+
+	assert.NoError(t, business.Delete(ctx, project.ID))
+	_, err = business.QueryMembers(ctx, project.ID)
+	assert.ErrorIs(t, err, projectbus.ErrNotFound)
 }
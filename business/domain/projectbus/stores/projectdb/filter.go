@@ -0,0 +1,36 @@
+package projectdb
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/sdk/sqldb"
+	"strings"
+)
+
+// applyFilter appends the WHERE clause for the filter to the query and
+// returns the arguments for its placeholders.
+func applyFilter(filter projectbus.QueryFilter, buf *strings.Builder) []any {
+	var wc []string
+	var args []any
+
+	if filter.Active != nil {
+		wc = append(wc, "active = ?")
+		args = append(args, *filter.Active)
+	}
+
+	if filter.CreatedBy != nil {
+		wc = append(wc, "created_by = ?")
+		args = append(args, *filter.CreatedBy)
+	}
+
+	if filter.Name != nil {
+		wc = append(wc, "name LIKE ? ESCAPE '!'")
+		args = append(args, "%"+sqldb.EscapeLike(*filter.Name)+"%")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+
+	return args
+}
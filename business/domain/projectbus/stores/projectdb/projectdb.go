@@ -0,0 +1,274 @@
+// Package projectdb contains project related CRUD functionality for MySQL.
+package projectdb
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Store manages the set of APIs for project database access.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new project along with its owner membership in one
+// transaction and returns the project ID.
+func (s *Store) Create(ctx context.Context, prj projectbus.Project, owner projectbus.Member) (int, error) {
+	var id int
+	err := sqldb.WithinTran(ctx, s.db, func(tx *sql.Tx) error {
+		query := "INSERT INTO project (name, active, created_at, created_by) VALUES (?, ?, ?, ?)"
+		result, err := sqldb.ExecContext(ctx, tx, query, prj.Name, prj.Active, prj.CreatedAt, prj.CreatedBy)
+		if err != nil {
+			return err
+		}
+
+		lastInsertID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		id = int(lastInsertID)
+
+		owner.ProjectID = id
+		return addMember(ctx, tx, owner)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Update replaces a project document in the database.
+func (s *Store) Update(ctx context.Context, prj projectbus.Project) error {
+	query := "UPDATE project SET name = ?, active = ? WHERE id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, prj.Name, prj.Active, prj.ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// Delete removes a project from the database by its ID.
+func (s *Store) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM project WHERE id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, id)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// HasTasks reports whether any task references the specified project.
+func (s *Store) HasTasks(ctx context.Context, id int) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM task WHERE project_id = ?)"
+
+	var hasTasks bool
+	if err := sqldb.QueryRowContext(ctx, s.db, query, id).Scan(&hasTasks); err != nil {
+		return false, err
+	}
+
+	return hasTasks, nil
+}
+
+// Query retrieves a page of the projects matching the filter from the database.
+func (s *Store) Query(ctx context.Context, filter projectbus.QueryFilter, orderBy []order.By, page page.Page) ([]projectbus.Project, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT id, name, active, created_at, created_by FROM project")
+
+	args := applyFilter(filter, &buf)
+
+	clause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(clause)
+
+	buf.WriteString(" LIMIT ? OFFSET ?")
+	args = append(args, page.RowsPerPage(), page.Offset())
+
+	rows, err := sqldb.QueryContext(ctx, s.db, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []projectbus.Project
+	for rows.Next() {
+		var project projectbus.Project
+		err := rows.Scan(&project.ID, &project.Name, &project.Active, &project.CreatedAt, &project.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// Count returns the total number of projects matching the filter.
+func (s *Store) Count(ctx context.Context, filter projectbus.QueryFilter) (int, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT COUNT(1) FROM project")
+
+	args := applyFilter(filter, &buf)
+
+	var count int
+	if err := sqldb.QueryRowContext(ctx, s.db, buf.String(), args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// QueryByID retrieves a specific project by its ID from the database.
+func (s *Store) QueryByID(ctx context.Context, id int) (projectbus.Project, error) {
+	query := "SELECT id, name, active, created_at, created_by FROM project WHERE id = ?"
+	row := sqldb.QueryRowContext(ctx, s.db, query, id)
+
+	var project projectbus.Project
+	err := row.Scan(&project.ID, &project.Name, &project.Active, &project.CreatedAt, &project.CreatedBy)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return projectbus.Project{}, fmt.Errorf("scan: %w", projectbus.ErrNotFound)
+		}
+		return projectbus.Project{}, err
+	}
+
+	return project, nil
+}
+
+// AddMember inserts a project member into the database.
+func (s *Store) AddMember(ctx context.Context, mbr projectbus.Member) error {
+	return addMember(ctx, s.db, mbr)
+}
+
+func addMember(ctx context.Context, db sqldb.ExecQueryer, mbr projectbus.Member) error {
+	query := "INSERT INTO project_member (project_id, user_id, role, created_at) VALUES (?, ?, ?, ?)"
+	_, err := sqldb.ExecContext(ctx, db, query, mbr.ProjectID, mbr.UserID, mbr.Role.String(), mbr.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("exec: %w", projectbus.ErrMemberExists)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a project in the database.
+func (s *Store) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	query := "DELETE FROM project_member WHERE project_id = ? AND user_id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	return checkMemberAffected(result)
+}
+
+// QueryMembers retrieves the members of a project in the order they joined.
+func (s *Store) QueryMembers(ctx context.Context, projectID int) ([]projectbus.Member, error) {
+	query := "SELECT project_id, user_id, role, created_at FROM project_member WHERE project_id = ? ORDER BY created_at ASC, user_id ASC"
+
+	rows, err := sqldb.QueryContext(ctx, s.db, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []projectbus.Member
+	for rows.Next() {
+		mbr, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, mbr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// QueryMember retrieves the membership of a user in a project from the
+// database.
+func (s *Store) QueryMember(ctx context.Context, projectID int, userID int) (projectbus.Member, error) {
+	query := "SELECT project_id, user_id, role, created_at FROM project_member WHERE project_id = ? AND user_id = ?"
+	row := sqldb.QueryRowContext(ctx, s.db, query, projectID, userID)
+
+	mbr, err := scanMember(row)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return projectbus.Member{}, fmt.Errorf("scan: %w", projectbus.ErrMemberNotFound)
+		}
+		return projectbus.Member{}, err
+	}
+
+	return mbr, nil
+}
+
+// scanMember reads a project_member row and parses its role.
+func scanMember(row interface{ Scan(dest ...any) error }) (projectbus.Member, error) {
+	var mbr projectbus.Member
+	var role string
+
+	err := row.Scan(&mbr.ProjectID, &mbr.UserID, &role, &mbr.CreatedAt)
+	if err != nil {
+		return projectbus.Member{}, err
+	}
+
+	if mbr.Role, err = projectbus.ParseMemberRole(role); err != nil {
+		return projectbus.Member{}, fmt.Errorf("projectID[%d] userID[%d]: %w", mbr.ProjectID, mbr.UserID, err)
+	}
+
+	return mbr, nil
+}
+
+// checkMemberAffected reports ErrMemberNotFound when the statement did not
+// match a row.
+func checkMemberAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("exec: %w", projectbus.ErrMemberNotFound)
+	}
+
+	return nil
+}
+
+// checkAffected reports ErrNotFound when the statement did not match a row.
+func checkAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("exec: %w", projectbus.ErrNotFound)
+	}
+
+	return nil
+}
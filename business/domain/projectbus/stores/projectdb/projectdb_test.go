@@ -0,0 +1,281 @@
+package projectdb_test
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/projectbus/stores/projectdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	db    *sql.DB
+	mock  sqlmock.Sqlmock
+	store *projectdb.Store
+)
+
+func setupMockDB(t *testing.T) {
+	var err error
+	db, mock, err = sqlmock.New()
+	assert.NoError(t, err)
+
+	store = projectdb.NewStore(db)
+}
+
+func mockProjectRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "name", "active", "created_at", "created_by"}).
+		AddRow(1, "Project 1", true, time.Now(), 1).
+		AddRow(2, "Project 2", false, time.Now(), 1)
+}
+
+func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO project \\(name, active, created_at, created_by\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs("New Project", true, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^INSERT INTO project_member \\(project_id, user_id, role, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)$").
+		WithArgs(1, 1, "owner", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx := context.Background()
+	prj := projectbus.Project{
+		Name:      "New Project",
+		Active:    true,
+		CreatedAt: time.Now(),
+		CreatedBy: 1,
+	}
+	owner := projectbus.Member{UserID: 1, Role: projectbus.MemberRoleOwner, CreatedAt: prj.CreatedAt}
+	id, err := store.Create(ctx, prj, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assertMockExpectations(t, mock)
+}
+
+func TestCreateOwnerFails(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO project ").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO project_member").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	prj := projectbus.Project{Name: "New Project", Active: true, CreatedAt: time.Now(), CreatedBy: 1}
+	owner := projectbus.Member{UserID: 1, Role: projectbus.MemberRoleOwner, CreatedAt: prj.CreatedAt}
+	_, err := store.Create(context.Background(), prj, owner)
+
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assertMockExpectations(t, mock)
+}
+
+func TestQuery(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT id, name, active, created_at, created_by FROM project ORDER BY id ASC LIMIT \\? OFFSET \\?$").
+		WithArgs(10, 0).
+		WillReturnRows(mockProjectRows())
+
+	ctx := context.Background()
+	projects, err := store.Query(ctx, projectbus.QueryFilter{}, []order.By{projectbus.DefaultOrderBy}, page.MustParse("1", "10"))
+
+	assert.NoError(t, err)
+	assert.Len(t, projects, 2)
+	assert.Equal(t, "Project 1", projects[0].Name)
+	assert.True(t, projects[0].Active)
+	assert.False(t, projects[1].Active)
+	assert.NotEmpty(t, projects[0].CreatedAt)
+	assert.True(t, projects[0].CreatedAt.After(time.Now().Add(-time.Hour)))
+	assert.Equal(t, 1, projects[0].CreatedBy)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryFilter(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	active := true
+	createdBy := 1
+	name := "home"
+	filter := projectbus.QueryFilter{Active: &active, CreatedBy: &createdBy, Name: &name}
+	orderBy := []order.By{order.NewBy(projectbus.OrderByName, order.DESC)}
+
+	mock.ExpectQuery("^SELECT id, name, active, created_at, created_by FROM project "+
+		"WHERE active = \\? AND created_by = \\? AND name LIKE \\? ESCAPE '!' ORDER BY name DESC, id LIMIT \\? OFFSET \\?$").
+		WithArgs(true, 1, "%home%", 20, 20).
+		WillReturnRows(mockProjectRows())
+
+	mock.ExpectQuery("^SELECT COUNT\\(1\\) FROM project WHERE active = \\? AND created_by = \\? AND name LIKE \\? ESCAPE '!'$").
+		WithArgs(true, 1, "%home%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(22))
+
+	ctx := context.Background()
+	projects, err := store.Query(ctx, filter, orderBy, page.MustParse("2", "20"))
+	assert.NoError(t, err)
+	assert.Len(t, projects, 2)
+
+	count, err := store.Count(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 22, count)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryByID(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name, active, created_at, created_by FROM project WHERE id = ?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "active", "created_at", "created_by"}).
+			AddRow(3, "Project 3", true, time.Now(), 3))
+
+	ctx := context.Background()
+	project, err := store.QueryByID(ctx, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Project 3", project.Name)
+	assert.True(t, project.Active)
+	assert.NotEmpty(t, project.CreatedAt)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestUpdate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^UPDATE project SET name = \\?, active = \\? WHERE id = \\?$").
+		WithArgs("Updated Project", false, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	prj := projectbus.Project{ID: 1, Name: "Updated Project", Active: false}
+	err := store.Update(ctx, prj)
+
+	assert.NoError(t, err)
+	assertMockExpectations(t, mock)
+}
+
+func TestHasTasks(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT EXISTS\\(SELECT 1 FROM task WHERE project_id = \\?\\)$").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	ctx := context.Background()
+	hasTasks, err := store.HasTasks(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.True(t, hasTasks)
+	assertMockExpectations(t, mock)
+}
+
+func TestDelete(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^DELETE FROM project WHERE id = \\?$").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	err := store.Delete(ctx, 1)
+
+	assert.NoError(t, err)
+	assertMockExpectations(t, mock)
+}
+
+func TestAddMember(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^INSERT INTO project_member \\(project_id, user_id, role, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)$").
+		WithArgs(1, 2, "maintainer", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO project_member").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-2' for key 'PRIMARY'"})
+
+	ctx := context.Background()
+	mbr := projectbus.Member{ProjectID: 1, UserID: 2, Role: projectbus.MemberRoleMaintainer, CreatedAt: time.Now()}
+
+	assert.NoError(t, store.AddMember(ctx, mbr))
+	assert.ErrorIs(t, store.AddMember(ctx, mbr), projectbus.ErrMemberExists)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryMembers(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"project_id", "user_id", "role", "created_at"}).
+		AddRow(1, 1, "owner", time.Now()).
+		AddRow(1, 2, "viewer", time.Now())
+
+	mock.ExpectQuery("^SELECT project_id, user_id, role, created_at FROM project_member WHERE project_id = \\? ORDER BY created_at ASC, user_id ASC$").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	ctx := context.Background()
+	members, err := store.QueryMembers(ctx, 1)
+
+	assert.NoError(t, err)
+	if assert.Len(t, members, 2) {
+		assert.Equal(t, projectbus.MemberRoleOwner, members[0].Role)
+		assert.Equal(t, projectbus.MemberRoleViewer, members[1].Role)
+	}
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryMemberNotFound(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT project_id, user_id, role, created_at FROM project_member WHERE project_id = \\? AND user_id = \\?$").
+		WithArgs(1, 9).
+		WillReturnError(sql.ErrNoRows)
+
+	ctx := context.Background()
+	_, err := store.QueryMember(ctx, 1, 9)
+
+	assert.ErrorIs(t, err, projectbus.ErrMemberNotFound)
+	assertMockExpectations(t, mock)
+}
+
+func TestRemoveMember(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^DELETE FROM project_member WHERE project_id = \\? AND user_id = \\?$").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^DELETE FROM project_member").
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+
+	assert.NoError(t, store.RemoveMember(ctx, 1, 2))
+	assert.ErrorIs(t, store.RemoveMember(ctx, 1, 9), projectbus.ErrMemberNotFound)
+	assertMockExpectations(t, mock)
+}
@@ -0,0 +1,24 @@
+package projectmem
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"strings"
+)
+
+// match reports whether the project satisfies every field set in the filter,
+// mirroring the WHERE clause the SQL store builds.
+func match(filter projectbus.QueryFilter, prj projectbus.Project) bool {
+	if filter.Active != nil && prj.Active != *filter.Active {
+		return false
+	}
+
+	if filter.CreatedBy != nil && prj.CreatedBy != *filter.CreatedBy {
+		return false
+	}
+
+	if filter.Name != nil && !strings.Contains(strings.ToLower(prj.Name), strings.ToLower(*filter.Name)) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,58 @@
+package projectmem
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/sdk/order"
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+var orderByFields = map[string]func(a, b projectbus.Project) int{
+	projectbus.OrderByID: func(a, b projectbus.Project) int {
+		return cmp.Compare(a.ID, b.ID)
+	},
+	projectbus.OrderByName: func(a, b projectbus.Project) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	projectbus.OrderByCreatedAt: func(a, b projectbus.Project) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	},
+	projectbus.OrderByCreatedBy: func(a, b projectbus.Project) int {
+		return cmp.Compare(a.CreatedBy, b.CreatedBy)
+	},
+}
+
+// compareProjects returns the comparison function for the list. Like the SQL
+// store, the id breaks the ties so the pages are stable.
+func compareProjects(orderBy []order.By) (func(a, b projectbus.Project) int, error) {
+	type key struct {
+		compare func(a, b projectbus.Project) int
+		desc    bool
+	}
+
+	keys := make([]key, 0, len(orderBy)+1)
+	for _, by := range orderBy {
+		compare, exists := orderByFields[by.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q does not exist", by.Field)
+		}
+		keys = append(keys, key{compare: compare, desc: by.Direction == order.DESC})
+	}
+	keys = append(keys, key{compare: orderByFields[projectbus.OrderByID]})
+
+	f := func(a, b projectbus.Project) int {
+		for _, k := range keys {
+			c := k.compare(a, b)
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	return f, nil
+}
@@ -0,0 +1,235 @@
+// Package projectmem contains project related CRUD functionality held in memory.
+package projectmem
+
+import (
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"context"
+	"slices"
+)
+
+const (
+	table       = "project"
+	memberTable = "project_member"
+)
+
+// Store manages the set of APIs for project in-memory access.
+type Store struct {
+	db *memdb.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *memdb.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new project along with its owner membership and returns
+// the project ID. The creator and the owner must exist.
+func (s *Store) Create(ctx context.Context, prj projectbus.Project, owner projectbus.Member) (int, error) {
+	var id int
+	err := s.db.Update(func(tx *memdb.Tx) error {
+		var err error
+		if id, err = tx.Insert(table, prj, refs(prj)...); err != nil {
+			return err
+		}
+
+		owner.ProjectID = id
+		_, err = tx.Insert(memberTable, owner, memberRefs(owner)...)
+		return err
+	})
+
+	return id, err
+}
+
+// Update replaces a stored project.
+func (s *Store) Update(ctx context.Context, prj projectbus.Project) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		exists, err := tx.Replace(table, prj.ID, prj, refs(prj)...)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return projectbus.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// Delete removes a project and its members by its ID. It fails while tasks
+// reference it.
+func (s *Store) Delete(ctx context.Context, id int) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		if _, exists := tx.Get(table, id); !exists {
+			return projectbus.ErrNotFound
+		}
+
+		for _, r := range tx.Rows(memberTable) {
+			if toBusMember(r.Value).ProjectID != id {
+				continue
+			}
+			if _, err := tx.Delete(memberTable, r.ID); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Delete(table, id)
+		return err
+	})
+}
+
+// HasTasks reports whether any task references the specified project.
+func (s *Store) HasTasks(ctx context.Context, id int) (bool, error) {
+	var hasTasks bool
+	s.db.View(func(tx *memdb.Tx) error {
+		hasTasks = tx.Referenced(table, id, "task")
+		return nil
+	})
+
+	return hasTasks, nil
+}
+
+// Query retrieves a page of the projects matching the filter.
+func (s *Store) Query(ctx context.Context, filter projectbus.QueryFilter, orderBy []order.By, page page.Page) ([]projectbus.Project, error) {
+	compare, err := compareProjects(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := s.filter(filter)
+	slices.SortFunc(projects, compare)
+
+	start := min(page.Offset(), len(projects))
+	end := min(start+page.RowsPerPage(), len(projects))
+
+	return projects[start:end], nil
+}
+
+// Count returns the total number of projects matching the filter.
+func (s *Store) Count(ctx context.Context, filter projectbus.QueryFilter) (int, error) {
+	return len(s.filter(filter)), nil
+}
+
+// QueryByID retrieves a specific project by its ID.
+func (s *Store) QueryByID(ctx context.Context, id int) (projectbus.Project, error) {
+	var prj projectbus.Project
+	err := s.db.View(func(tx *memdb.Tx) error {
+		v, exists := tx.Get(table, id)
+		if !exists {
+			return projectbus.ErrNotFound
+		}
+
+		prj = toBusProject(id, v)
+		return nil
+	})
+
+	return prj, err
+}
+
+// AddMember inserts a project member. The project and the user must exist
+// and the user must not be a member already.
+func (s *Store) AddMember(ctx context.Context, mbr projectbus.Member) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		if _, exists := findMember(tx, mbr.ProjectID, mbr.UserID); exists {
+			return projectbus.ErrMemberExists
+		}
+
+		_, err := tx.Insert(memberTable, mbr, memberRefs(mbr)...)
+		return err
+	})
+}
+
+// RemoveMember removes a user from a project.
+func (s *Store) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		id, exists := findMember(tx, projectID, userID)
+		if !exists {
+			return projectbus.ErrMemberNotFound
+		}
+
+		_, err := tx.Delete(memberTable, id)
+		return err
+	})
+}
+
+// QueryMembers retrieves the members of a project in the order they joined.
+func (s *Store) QueryMembers(ctx context.Context, projectID int) ([]projectbus.Member, error) {
+	var members []projectbus.Member
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(memberTable) {
+			if mbr := toBusMember(r.Value); mbr.ProjectID == projectID {
+				members = append(members, mbr)
+			}
+		}
+		return nil
+	})
+
+	return members, nil
+}
+
+// QueryMember retrieves the membership of a user in a project.
+func (s *Store) QueryMember(ctx context.Context, projectID int, userID int) (projectbus.Member, error) {
+	var mbr projectbus.Member
+	err := s.db.View(func(tx *memdb.Tx) error {
+		id, exists := findMember(tx, projectID, userID)
+		if !exists {
+			return projectbus.ErrMemberNotFound
+		}
+
+		v, _ := tx.Get(memberTable, id)
+		mbr = toBusMember(v)
+		return nil
+	})
+
+	return mbr, err
+}
+
+// filter returns the projects matching the filter in id order.
+func (s *Store) filter(filter projectbus.QueryFilter) []projectbus.Project {
+	var projects []projectbus.Project
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			if prj := toBusProject(r.ID, r.Value); match(filter, prj) {
+				projects = append(projects, prj)
+			}
+		}
+		return nil
+	})
+
+	return projects
+}
+
+func refs(prj projectbus.Project) []memdb.Ref {
+	return []memdb.Ref{{Table: "users", ID: prj.CreatedBy}}
+}
+
+// findMember returns the row ID of the membership, the in-memory stand-in
+// for the composite primary key of the SQL table.
+func findMember(tx *memdb.Tx, projectID int, userID int) (int, bool) {
+	for _, r := range tx.Rows(memberTable) {
+		if mbr := toBusMember(r.Value); mbr.ProjectID == projectID && mbr.UserID == userID {
+			return r.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+func memberRefs(mbr projectbus.Member) []memdb.Ref {
+	return []memdb.Ref{
+		{Table: table, ID: mbr.ProjectID},
+		{Table: "users", ID: mbr.UserID},
+	}
+}
+
+func toBusMember(v any) projectbus.Member {
+	return v.(projectbus.Member)
+}
+
+func toBusProject(id int, v any) projectbus.Project {
+	prj := v.(projectbus.Project)
+	prj.ID = id
+	return prj
+}
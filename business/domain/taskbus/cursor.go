@@ -0,0 +1,19 @@
+package taskbus
+
+import "time"
+
+// Cursor marks the position of a task in the keyset order used to walk large
+// listings, which is (created_at, id) ascending. Unlike an offset, it stays
+// valid while tasks are created or deleted between the pages.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// CursorOf returns the cursor pointing at the task.
+func CursorOf(task Task) Cursor {
+	return Cursor{
+		CreatedAt: task.CreatedAt,
+		ID:        task.ID,
+	}
+}
@@ -0,0 +1,207 @@
+package taskbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// AddDependency records that the task is blocked by another task, which may
+// belong to another project. A task can not wait on itself, directly or
+// through the tasks blocking it, and a done task can not get an open blocker.
+func (s *Business) AddDependency(ctx context.Context, taskID int, blockerID int) (Dependency, error) {
+	task, err := s.storer.QueryByID(ctx, taskID)
+	if err != nil {
+		return Dependency{}, fmt.Errorf("query: taskID[%d]: %w", taskID, err)
+	}
+
+	blocker, err := s.storer.QueryByID(ctx, blockerID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Dependency{}, fmt.Errorf("blocker taskID[%d] does not exist: %w", blockerID, ErrInvalidBlocker)
+		}
+		return Dependency{}, fmt.Errorf("query: blocker taskID[%d]: %w", blockerID, err)
+	}
+
+	// A done task needs its blockers closed, as Transition and Reopen
+	// already make sure of.
+	if task.Status == StatusDone && blocker.Status != StatusDone && blocker.Status != StatusCancelled {
+		return Dependency{}, fmt.Errorf("taskID[%d] is done and blocker taskID[%d] is %s: %w", taskID, blockerID, blocker.Status, ErrOpenBlockers)
+	}
+
+	if err := s.checkCycle(ctx, taskID, blockerID); err != nil {
+		return Dependency{}, err
+	}
+
+	dep := Dependency{
+		TaskID:    taskID,
+		BlockerID: blockerID,
+		CreatedAt: nowUTC(),
+	}
+
+	if err := s.storer.AddDependency(ctx, dep); err != nil {
+		return Dependency{}, fmt.Errorf("add dependency: taskID[%d] blocker taskID[%d]: %w", taskID, blockerID, err)
+	}
+
+	return dep, nil
+}
+
+// RemoveDependency removes the link between a task and its blocker.
+func (s *Business) RemoveDependency(ctx context.Context, taskID int, blockerID int) error {
+	if err := s.storer.RemoveDependency(ctx, taskID, blockerID); err != nil {
+		return fmt.Errorf("remove dependency: taskID[%d] blocker taskID[%d]: %w", taskID, blockerID, err)
+	}
+
+	return nil
+}
+
+// QueryBlockers retrieves the tasks blocking a task in id order, finished
+// ones included.
+func (s *Business) QueryBlockers(ctx context.Context, id int) ([]Task, error) {
+	if _, err := s.storer.QueryByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("query: taskID[%d]: %w", id, err)
+	}
+
+	blockers, err := s.storer.QueryBlockers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("query blockers: taskID[%d]: %w", id, err)
+	}
+
+	return blockers, nil
+}
+
+// CriticalPath retrieves the longest chain of open tasks of the project
+// where each task is blocked by the one before it. Done and cancelled tasks
+// hold nothing up, so they are left out, and so are blockers from other
+// projects. A project without such links has an empty path.
+func (s *Business) CriticalPath(ctx context.Context, projectID int) ([]Task, error) {
+	if _, err := s.projectBus.QueryById(ctx, projectID); err != nil {
+		return nil, fmt.Errorf("critical path: %w", err)
+	}
+
+	deps, err := s.storer.QueryOpenDependencies(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query dependencies: projectID[%d]: %w", projectID, err)
+	}
+
+	ids := criticalPath(deps)
+
+	tasks := make([]Task, len(ids))
+	for i, id := range ids {
+		if tasks[i], err = s.storer.QueryByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("query: taskID[%d]: %w", id, err)
+		}
+	}
+
+	return tasks, nil
+}
+
+// openBlockers returns the tasks blocking the task that are neither done
+// nor cancelled.
+func (s *Business) openBlockers(ctx context.Context, id int) ([]Task, error) {
+	blockers, err := s.storer.QueryBlockers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("query blockers: taskID[%d]: %w", id, err)
+	}
+
+	return slices.DeleteFunc(blockers, func(blocker Task) bool {
+		return blocker.Status == StatusDone || blocker.Status == StatusCancelled
+	}), nil
+}
+
+// checkCycle verifies the task is not the blocker itself nor one of the
+// tasks the blocker waits on, following the links across projects.
+func (s *Business) checkCycle(ctx context.Context, taskID int, blockerID int) error {
+	visited := make(map[int]bool)
+
+	stack := []int{blockerID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if id == taskID {
+			return fmt.Errorf("taskID[%d] blocker taskID[%d]: %w", taskID, blockerID, ErrDependencyCycle)
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		blockers, err := s.storer.QueryBlockers(ctx, id)
+		if err != nil {
+			return fmt.Errorf("query blockers: taskID[%d]: %w", id, err)
+		}
+
+		for _, blocker := range blockers {
+			stack = append(stack, blocker.ID)
+		}
+	}
+
+	return nil
+}
+
+// criticalPath returns the ids of the longest chain of the dependencies,
+// first blocker first. Ties go to the lowest ids so the same links always
+// give the same path.
+func criticalPath(deps []Dependency) []int {
+	blockers := make(map[int][]int)
+	for _, dep := range deps {
+		blockers[dep.TaskID] = append(blockers[dep.TaskID], dep.BlockerID)
+		if _, exists := blockers[dep.BlockerID]; !exists {
+			blockers[dep.BlockerID] = nil
+		}
+	}
+
+	ids := make([]int, 0, len(blockers))
+	for id := range blockers {
+		slices.Sort(blockers[id])
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	// length holds the number of tasks of the longest chain ending at each
+	// task and next the blocker that chain goes through. Seeding the length
+	// before walking the blockers stops the walk should a cycle have slipped
+	// in through concurrent additions.
+	length := make(map[int]int)
+	next := make(map[int]int)
+
+	var walk func(id int) int
+	walk = func(id int) int {
+		if n, exists := length[id]; exists {
+			return n
+		}
+		length[id] = 1
+
+		best := 0
+		for _, blocker := range blockers[id] {
+			if n := walk(blocker); n > best {
+				best = n
+				next[id] = blocker
+			}
+		}
+
+		length[id] = best + 1
+		return best + 1
+	}
+
+	end, longest := 0, 0
+	for _, id := range ids {
+		if n := walk(id); n > longest {
+			end, longest = id, n
+		}
+	}
+
+	if longest < 2 {
+		return nil
+	}
+
+	path := []int{end}
+	for id, exists := next[end]; exists && !slices.Contains(path, id); id, exists = next[id] {
+		path = append(path, id)
+	}
+	slices.Reverse(path)
+
+	return path
+}
@@ -0,0 +1,68 @@
+package taskbus
+
+import "time"
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field is not filtered on. Open selects the tasks that are neither
+// done nor cancelled.
+type QueryFilter struct {
+	ProjectID         *int
+	AssignedTo        *int
+	CreatedBy         *int
+	ParentID          *int
+	Finished          *bool
+	Status            *Status
+	Open              *bool
+	Priority          *Priority
+	StartCreatedDate  *time.Time
+	EndCreatedDate    *time.Time
+	StartFinishedDate *time.Time
+	EndFinishedDate   *time.Time
+	StartDueDate      *time.Time
+	EndDueDate        *time.Time
+	Title             *string
+}
+
+// Overdue narrows the filter to the open tasks that were due by now.
+func (f QueryFilter) Overdue(now time.Time) QueryFilter {
+	return f.openDue(time.Time{}, now)
+}
+
+// DueToday narrows the filter to the open tasks due on the day of now. The
+// day starts at midnight in the location of now, so callers pass the time in
+// the zone of the user asking.
+func (f QueryFilter) DueToday(now time.Time) QueryFilter {
+	start := startOfDay(now)
+	return f.openDue(start, start.AddDate(0, 0, 1).Add(-time.Second))
+}
+
+// DueThisWeek narrows the filter to the open tasks due in the week of now,
+// from Monday to Sunday in the location of now.
+func (f QueryFilter) DueThisWeek(now time.Time) QueryFilter {
+	start := startOfDay(now)
+	start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	return f.openDue(start, start.AddDate(0, 0, 7).Add(-time.Second))
+}
+
+// openDue selects the open tasks due between start and end, both inclusive.
+// A zero start leaves the window open on that side. Due dates are stored in
+// UTC, so the bounds are converted for the stores to compare them.
+func (f QueryFilter) openDue(start time.Time, end time.Time) QueryFilter {
+	open := true
+	f.Open = &open
+
+	f.StartDueDate = nil
+	if !start.IsZero() {
+		start = start.UTC()
+		f.StartDueDate = &start
+	}
+
+	end = end.UTC()
+	f.EndDueDate = &end
+
+	return f
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -5,7 +5,8 @@ import (
 	"time"
 )
 
-// Task represents a task in the system.
+// Task represents a task in the system. DueAt is kept in UTC and LateBy is
+// how long after it the task was finished, zero when it was on time.
 type Task struct {
 	ID          int           `json:"id"`
 	Title       string        `json:"title"`
@@ -15,15 +16,25 @@ type Task struct {
 	FinishedAt  sql.NullTime  `json:"finished_at"`
 	CreatedBy   int           `json:"created_by"`
 	AssignedTo  sql.NullInt32 `json:"assigned_to"`
+	Status      Status        `json:"status"`
+	ReopenCount int           `json:"reopen_count"`
+	Priority    Priority      `json:"priority"`
+	DueAt       sql.NullTime  `json:"due_at"`
+	LateBy      time.Duration `json:"late_by"`
+	ParentID    sql.NullInt32 `json:"parent_id"`
 }
 
-// NewTask represents a new task to be created.
+// NewTask represents a new task to be created. A task without a priority
+// gets the medium one.
 type NewTask struct {
 	Title       string
 	Description string
 	ProjectID   int
 	CreatedBy   int
 	AssignedTo  sql.NullInt32
+	Priority    Priority
+	DueAt       sql.NullTime
+	ParentID    sql.NullInt32
 }
 
 // UpdateTask represents a task with updates to be applied.
@@ -31,4 +42,34 @@ type UpdateTask struct {
 	Title       string
 	Description string
 	AssignedTo  sql.NullInt32
+	Priority    Priority
+	DueAt       sql.NullTime
+	ParentID    sql.NullInt32
+}
+
+// Progress counts the direct subtasks of a task and how many of them are
+// done. Cancelled subtasks are left out of both counts.
+type Progress struct {
+	Done  int
+	Total int
+}
+
+// Dependency records that a task can not be finished before its blocker is.
+// Both tasks may belong to different projects.
+type Dependency struct {
+	TaskID    int       `json:"task_id"`
+	BlockerID int       `json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Transition records a change of status of a task. ChangedBy is empty for
+// changes made by the admin tool and Reason is only set on reopenings.
+type Transition struct {
+	ID        int           `json:"id"`
+	TaskID    int           `json:"task_id"`
+	From      Status        `json:"from"`
+	To        Status        `json:"to"`
+	ChangedBy sql.NullInt32 `json:"changed_by"`
+	ChangedAt time.Time     `json:"changed_at"`
+	Reason    string        `json:"reason"`
 }
@@ -0,0 +1,20 @@
+package taskbus
+
+import "TODO-list/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "id"
+	OrderByTitle       = "title"
+	OrderByProjectID   = "project_id"
+	OrderByCreatedAt   = "created_at"
+	OrderByFinishedAt  = "finished_at"
+	OrderByCreatedBy   = "created_by"
+	OrderByAssignedTo  = "assigned_to"
+	OrderByReopenCount = "reopen_count"
+	OrderByPriority    = "priority"
+	OrderByDueAt       = "due_at"
+)
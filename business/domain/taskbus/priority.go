@@ -0,0 +1,70 @@
+package taskbus
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// The set of priorities a task can have, from the least to the most
+// pressing.
+var (
+	PriorityLow    = Priority{"low", 0}
+	PriorityMedium = Priority{"medium", 1}
+	PriorityHigh   = Priority{"high", 2}
+	PriorityUrgent = Priority{"urgent", 3}
+)
+
+var priorities = map[string]Priority{
+	PriorityLow.name:    PriorityLow,
+	PriorityMedium.name: PriorityMedium,
+	PriorityHigh.name:   PriorityHigh,
+	PriorityUrgent.name: PriorityUrgent,
+}
+
+// Priority represents how pressing a task is.
+type Priority struct {
+	name  string
+	level int
+}
+
+// ParsePriority parses the string value and returns a priority if one exists.
+func ParsePriority(value string) (Priority, error) {
+	priority, exists := priorities[value]
+	if !exists {
+		return Priority{}, fmt.Errorf("invalid priority %q", value)
+	}
+
+	return priority, nil
+}
+
+// MustParsePriority parses the string value and returns a priority if one
+// exists. If an error occurs the function panics.
+func MustParsePriority(value string) Priority {
+	priority, err := ParsePriority(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return priority
+}
+
+// Compare returns -1, 0 or +1 as the priority is less, as or more pressing
+// than the other.
+func (p Priority) Compare(p2 Priority) int {
+	return cmp.Compare(p.level, p2.level)
+}
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	return p.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (p Priority) Equal(p2 Priority) bool {
+	return p.name == p2.name
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.name), nil
+}
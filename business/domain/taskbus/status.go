@@ -0,0 +1,83 @@
+package taskbus
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The set of statuses a task moves through.
+var (
+	StatusTodo       = Status{"todo"}
+	StatusInProgress = Status{"in_progress"}
+	StatusInReview   = Status{"in_review"}
+	StatusDone       = Status{"done"}
+	StatusBlocked    = Status{"blocked"}
+	StatusCancelled  = Status{"cancelled"}
+)
+
+var statuses = map[string]Status{
+	StatusTodo.name:       StatusTodo,
+	StatusInProgress.name: StatusInProgress,
+	StatusInReview.name:   StatusInReview,
+	StatusDone.name:       StatusDone,
+	StatusBlocked.name:    StatusBlocked,
+	StatusCancelled.name:  StatusCancelled,
+}
+
+// transitions lists the statuses each status may move to. Tasks can skip the
+// review and be finished from any active status but blocked, so finishing a
+// task keeps working for teams that do not use the full workflow.
+var transitions = map[Status][]Status{
+	StatusTodo:       {StatusInProgress, StatusDone, StatusBlocked, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusInReview, StatusDone, StatusBlocked, StatusCancelled},
+	StatusInReview:   {StatusInProgress, StatusDone, StatusBlocked, StatusCancelled},
+	StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+	StatusDone:       {},
+	StatusCancelled:  {},
+}
+
+// Status represents the stage of the workflow a task is in.
+type Status struct {
+	name string
+}
+
+// ParseStatus parses the string value and returns a status if one exists.
+func ParseStatus(value string) (Status, error) {
+	status, exists := statuses[value]
+	if !exists {
+		return Status{}, fmt.Errorf("invalid status %q", value)
+	}
+
+	return status, nil
+}
+
+// MustParseStatus parses the string value and returns a status if one
+// exists. If an error occurs the function panics.
+func MustParseStatus(value string) Status {
+	status, err := ParseStatus(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return status
+}
+
+// CanTransition reports whether a task in this status may move to the other.
+func (s Status) CanTransition(to Status) bool {
+	return slices.Contains(transitions[s], to)
+}
+
+// String returns the name of the status.
+func (s Status) String() string {
+	return s.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s Status) Equal(s2 Status) bool {
+	return s.name == s2.name
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.name), nil
+}
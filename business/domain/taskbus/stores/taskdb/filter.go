@@ -0,0 +1,117 @@
+package taskdb
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/sdk/sqldb"
+	"strings"
+)
+
+// applyFilter appends the WHERE clause for the filter to the query and
+// returns the arguments for its placeholders.
+func applyFilter(filter taskbus.QueryFilter, buf *strings.Builder) []any {
+	wc, args := filterClauses(filter)
+	writeWhere(wc, buf)
+
+	return args
+}
+
+// filterClauses returns the conditions for the filter along with the
+// arguments for their placeholders. SQLite keeps dates as text with the
+// offset they were written in, so the bounds are passed in UTC like the
+// stored dates to compare them.
+func filterClauses(filter taskbus.QueryFilter) ([]string, []any) {
+	var wc []string
+	var args []any
+
+	if filter.ProjectID != nil {
+		wc = append(wc, "project_id = ?")
+		args = append(args, *filter.ProjectID)
+	}
+
+	if filter.AssignedTo != nil {
+		wc = append(wc, "assigned_to = ?")
+		args = append(args, *filter.AssignedTo)
+	}
+
+	if filter.CreatedBy != nil {
+		wc = append(wc, "created_by = ?")
+		args = append(args, *filter.CreatedBy)
+	}
+
+	if filter.ParentID != nil {
+		wc = append(wc, "parent_id = ?")
+		args = append(args, *filter.ParentID)
+	}
+
+	if filter.Finished != nil {
+		if *filter.Finished {
+			wc = append(wc, "finished_at IS NOT NULL")
+		} else {
+			wc = append(wc, "finished_at IS NULL")
+		}
+	}
+
+	if filter.Status != nil {
+		wc = append(wc, "status = ?")
+		args = append(args, filter.Status.String())
+	}
+
+	if filter.Open != nil {
+		if *filter.Open {
+			wc = append(wc, "status NOT IN (?, ?)")
+		} else {
+			wc = append(wc, "status IN (?, ?)")
+		}
+		args = append(args, taskbus.StatusDone.String(), taskbus.StatusCancelled.String())
+	}
+
+	if filter.Priority != nil {
+		wc = append(wc, "priority = ?")
+		args = append(args, filter.Priority.String())
+	}
+
+	if filter.StartCreatedDate != nil {
+		wc = append(wc, "created_at >= ?")
+		args = append(args, filter.StartCreatedDate.UTC())
+	}
+
+	if filter.EndCreatedDate != nil {
+		wc = append(wc, "created_at <= ?")
+		args = append(args, filter.EndCreatedDate.UTC())
+	}
+
+	if filter.StartFinishedDate != nil {
+		wc = append(wc, "finished_at >= ?")
+		args = append(args, filter.StartFinishedDate.UTC())
+	}
+
+	if filter.EndFinishedDate != nil {
+		wc = append(wc, "finished_at <= ?")
+		args = append(args, filter.EndFinishedDate.UTC())
+	}
+
+	if filter.StartDueDate != nil {
+		wc = append(wc, "due_at >= ?")
+		args = append(args, filter.StartDueDate.UTC())
+	}
+
+	if filter.EndDueDate != nil {
+		wc = append(wc, "due_at <= ?")
+		args = append(args, filter.EndDueDate.UTC())
+	}
+
+	if filter.Title != nil {
+		wc = append(wc, "title LIKE ? ESCAPE '!'")
+		args = append(args, "%"+sqldb.EscapeLike(*filter.Title)+"%")
+	}
+
+	return wc, args
+}
+
+// writeWhere appends the conditions to the query joined by AND.
+func writeWhere(wc []string, buf *strings.Builder) {
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,53 @@
+package taskdb
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/sdk/order"
+	"fmt"
+	"strings"
+)
+
+// orderByFields maps the fields to the expressions they sort on. The
+// priority is stored by name, so it is ranked from low to urgent.
+var orderByFields = map[string]string{
+	taskbus.OrderByID:          "id",
+	taskbus.OrderByTitle:       "title",
+	taskbus.OrderByProjectID:   "project_id",
+	taskbus.OrderByCreatedAt:   "created_at",
+	taskbus.OrderByFinishedAt:  "finished_at",
+	taskbus.OrderByCreatedBy:   "created_by",
+	taskbus.OrderByAssignedTo:  "assigned_to",
+	taskbus.OrderByReopenCount: "reopen_count",
+	taskbus.OrderByPriority:    "CASE priority WHEN 'low' THEN 0 WHEN 'medium' THEN 1 WHEN 'high' THEN 2 ELSE 3 END",
+	taskbus.OrderByDueAt:       "due_at",
+}
+
+// orderByClause returns the ORDER BY clause for the list. The id is added as
+// the last key when missing so pages are stable when the other fields tie.
+func orderByClause(orderBy []order.By) (string, error) {
+	var fields []string
+	var hasID bool
+	for _, by := range orderBy {
+		field, err := orderByField(by)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, field)
+		hasID = hasID || by.Field == taskbus.OrderByID
+	}
+
+	if !hasID {
+		fields = append(fields, "id")
+	}
+
+	return " ORDER BY " + strings.Join(fields, ", "), nil
+}
+
+func orderByField(by order.By) (string, error) {
+	column, exists := orderByFields[by.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", by.Field)
+	}
+
+	return column + " " + by.Direction, nil
+}
@@ -0,0 +1,473 @@
+// Package taskdb contains task related CRUD functionality for MySQL.
+package taskdb
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store manages the set of APIs for task database access.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new task into the database and returns its ID.
+func (s *Store) Create(ctx context.Context, task taskbus.Task) (int, error) {
+	query := "INSERT INTO task (title, description, created_by, assigned_to, project_id, created_at, finished_at, status, priority, due_at, parent_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := sqldb.ExecContext(ctx, s.db, query, task.Title, task.Description, task.CreatedBy, task.AssignedTo, task.ProjectID, task.CreatedAt, task.FinishedAt, task.Status.String(), task.Priority.String(), task.DueAt, task.ParentID)
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastInsertID), nil
+}
+
+// Update stores the editable fields of a task. The status and the finish
+// time are left to Transition.
+func (s *Store) Update(ctx context.Context, task taskbus.Task) error {
+	query := "UPDATE task SET title = ?, description = ?, assigned_to = ?, priority = ?, due_at = ?, parent_id = ? WHERE id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, task.Title, task.Description, task.AssignedTo, task.Priority.String(), task.DueAt, task.ParentID, task.ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// Delete removes a task from the database by its ID. Its dependencies go
+// with it through the foreign keys.
+func (s *Store) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM task WHERE id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, id)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// Query retrieves a page of the tasks matching the filter from the database.
+func (s *Store) Query(ctx context.Context, filter taskbus.QueryFilter, orderBy []order.By, page page.Page) ([]taskbus.Task, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task")
+
+	args := applyFilter(filter, &buf)
+
+	clause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(clause)
+
+	buf.WriteString(" LIMIT ? OFFSET ?")
+	args = append(args, page.RowsPerPage(), page.Offset())
+
+	rows, err := sqldb.QueryContext(ctx, s.db, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []taskbus.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// QueryAfter retrieves up to limit tasks matching the filter that come after
+// the cursor in (created_at, id) order. The comparison is spelled out instead
+// of using a row value so both MySQL and SQLite can use the
+// idx_task_created_at_id index.
+func (s *Store) QueryAfter(ctx context.Context, filter taskbus.QueryFilter, after *taskbus.Cursor, limit int) ([]taskbus.Task, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task")
+
+	wc, args := filterClauses(filter)
+	if after != nil {
+		wc = append(wc, "(created_at > ? OR (created_at = ? AND id > ?))")
+		args = append(args, after.CreatedAt.UTC(), after.CreatedAt.UTC(), after.ID)
+	}
+	writeWhere(wc, &buf)
+
+	buf.WriteString(" ORDER BY created_at, id LIMIT ?")
+	args = append(args, limit)
+
+	rows, err := sqldb.QueryContext(ctx, s.db, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []taskbus.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Count returns the total number of tasks matching the filter.
+func (s *Store) Count(ctx context.Context, filter taskbus.QueryFilter) (int, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT COUNT(1) FROM task")
+
+	args := applyFilter(filter, &buf)
+
+	var count int
+	if err := sqldb.QueryRowContext(ctx, s.db, buf.String(), args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// QueryByID retrieves a task by its ID.
+func (s *Store) QueryByID(ctx context.Context, id int) (taskbus.Task, error) {
+	query := "SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task WHERE id = ?"
+	row := sqldb.QueryRowContext(ctx, s.db, query, id)
+
+	task, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return taskbus.Task{}, fmt.Errorf("scan: %w", taskbus.ErrNotFound)
+		}
+		return taskbus.Task{}, err
+	}
+
+	return task, nil
+}
+
+// Transition stores the new status, finish time, lateness and reopen count of
+// the task, provided it still has the status the transition starts from, and
+// records the transition. Both happen in one transaction so no change of
+// status goes unrecorded.
+func (s *Store) Transition(ctx context.Context, task taskbus.Task, tr taskbus.Transition) error {
+	return s.TransitionAll(ctx, []taskbus.Task{task}, []taskbus.Transition{tr})
+}
+
+// TransitionAll does what Transition does for each task and the transition
+// at the same index, all in one transaction, so either every task moves or
+// none does.
+func (s *Store) TransitionAll(ctx context.Context, tasks []taskbus.Task, trs []taskbus.Transition) error {
+	return sqldb.WithinTran(ctx, s.db, func(tx *sql.Tx) error {
+		for i, task := range tasks {
+			if err := transition(ctx, tx, task, trs[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func transition(ctx context.Context, tx *sql.Tx, task taskbus.Task, tr taskbus.Transition) error {
+	query := "UPDATE task SET status = ?, finished_at = ?, late_seconds = ?, reopen_count = ? WHERE id = ? AND status = ?"
+	result, err := sqldb.ExecContext(ctx, tx, query, task.Status.String(), task.FinishedAt, int64(task.LateBy/time.Second), task.ReopenCount, task.ID, tr.From.String())
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// The task changed status or was deleted since it was read.
+	if rows == 0 {
+		return fmt.Errorf("exec: taskID[%d] is no longer %s: %w", task.ID, tr.From, taskbus.ErrInvalidTransition)
+	}
+
+	query = "INSERT INTO task_transition (task_id, from_status, to_status, changed_by, changed_at, reason) VALUES (?, ?, ?, ?, ?, ?)"
+	if _, err := sqldb.ExecContext(ctx, tx, query, tr.TaskID, tr.From.String(), tr.To.String(), tr.ChangedBy, tr.ChangedAt, tr.Reason); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// QueryTransitions retrieves the status changes of a task, oldest first.
+func (s *Store) QueryTransitions(ctx context.Context, taskID int) ([]taskbus.Transition, error) {
+	query := "SELECT id, task_id, from_status, to_status, changed_by, changed_at, reason FROM task_transition WHERE task_id = ? ORDER BY changed_at, id"
+
+	rows, err := sqldb.QueryContext(ctx, s.db, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trs []taskbus.Transition
+	for rows.Next() {
+		var tr taskbus.Transition
+		var from, to string
+
+		if err := rows.Scan(&tr.ID, &tr.TaskID, &from, &to, &tr.ChangedBy, &tr.ChangedAt, &tr.Reason); err != nil {
+			return nil, err
+		}
+
+		if tr.From, err = taskbus.ParseStatus(from); err != nil {
+			return nil, fmt.Errorf("transitionID[%d]: %w", tr.ID, err)
+		}
+		if tr.To, err = taskbus.ParseStatus(to); err != nil {
+			return nil, fmt.Errorf("transitionID[%d]: %w", tr.ID, err)
+		}
+
+		trs = append(trs, tr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trs, nil
+}
+
+// QueryChildren retrieves the direct subtasks of a task in id order.
+func (s *Store) QueryChildren(ctx context.Context, parentID int) ([]taskbus.Task, error) {
+	query := "SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task WHERE parent_id = ? ORDER BY id"
+
+	rows, err := sqldb.QueryContext(ctx, s.db, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []taskbus.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// QueryProgress counts the done and the total direct subtasks of each task
+// in one grouped query, leaving the cancelled ones out.
+func (s *Store) QueryProgress(ctx context.Context, parentIDs []int) (map[int]taskbus.Progress, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT parent_id, SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), COUNT(1) FROM task WHERE status <> ? AND parent_id IN (")
+
+	args := []any{taskbus.StatusDone.String(), taskbus.StatusCancelled.String()}
+	for i, id := range parentIDs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("?")
+		args = append(args, id)
+	}
+	buf.WriteString(") GROUP BY parent_id")
+
+	rows, err := sqldb.QueryContext(ctx, s.db, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[int]taskbus.Progress)
+	for rows.Next() {
+		var parentID int
+		var p taskbus.Progress
+		if err := rows.Scan(&parentID, &p.Done, &p.Total); err != nil {
+			return nil, err
+		}
+		progress[parentID] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// AddDependency records that a task is blocked by another one.
+func (s *Store) AddDependency(ctx context.Context, dep taskbus.Dependency) error {
+	query := "INSERT INTO task_dependency (task_id, blocker_id, created_at) VALUES (?, ?, ?)"
+	_, err := sqldb.ExecContext(ctx, s.db, query, dep.TaskID, dep.BlockerID, dep.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("exec: %w", taskbus.ErrDependencyExists)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveDependency removes the link between a task and its blocker.
+func (s *Store) RemoveDependency(ctx context.Context, taskID int, blockerID int) error {
+	query := "DELETE FROM task_dependency WHERE task_id = ? AND blocker_id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, taskID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("exec: %w", taskbus.ErrDependencyNotFound)
+	}
+
+	return nil
+}
+
+// QueryBlockers retrieves the tasks blocking a task in id order.
+func (s *Store) QueryBlockers(ctx context.Context, taskID int) ([]taskbus.Task, error) {
+	query := "SELECT t.id, t.title, t.description, t.created_at, t.finished_at, t.created_by, t.assigned_to, t.project_id, t.status, t.reopen_count, t.priority, t.due_at, t.late_seconds, t.parent_id FROM task t JOIN task_dependency d ON d.blocker_id = t.id WHERE d.task_id = ? ORDER BY t.id"
+
+	rows, err := sqldb.QueryContext(ctx, s.db, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []taskbus.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// QueryDependents retrieves the tasks blocked by a task in id order.
+func (s *Store) QueryDependents(ctx context.Context, blockerID int) ([]taskbus.Task, error) {
+	query := "SELECT t.id, t.title, t.description, t.created_at, t.finished_at, t.created_by, t.assigned_to, t.project_id, t.status, t.reopen_count, t.priority, t.due_at, t.late_seconds, t.parent_id FROM task t JOIN task_dependency d ON d.task_id = t.id WHERE d.blocker_id = ? ORDER BY t.id"
+
+	rows, err := sqldb.QueryContext(ctx, s.db, query, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []taskbus.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// QueryOpenDependencies retrieves the links between the tasks of a project
+// that are neither done nor cancelled.
+func (s *Store) QueryOpenDependencies(ctx context.Context, projectID int) ([]taskbus.Dependency, error) {
+	query := "SELECT d.task_id, d.blocker_id, d.created_at FROM task_dependency d JOIN task t ON t.id = d.task_id JOIN task b ON b.id = d.blocker_id WHERE t.project_id = ? AND b.project_id = ? AND t.status NOT IN (?, ?) AND b.status NOT IN (?, ?) ORDER BY d.task_id, d.blocker_id"
+	done, cancelled := taskbus.StatusDone.String(), taskbus.StatusCancelled.String()
+
+	rows, err := sqldb.QueryContext(ctx, s.db, query, projectID, projectID, done, cancelled, done, cancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deps []taskbus.Dependency
+	for rows.Next() {
+		var dep taskbus.Dependency
+		if err := rows.Scan(&dep.TaskID, &dep.BlockerID, &dep.CreatedAt); err != nil {
+			return nil, err
+		}
+		deps = append(deps, dep)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// scanTask reads a task row and parses its status and priority.
+func scanTask(row interface{ Scan(dest ...any) error }) (taskbus.Task, error) {
+	var task taskbus.Task
+	var status, priority string
+	var lateSeconds int64
+
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.CreatedAt, &task.FinishedAt, &task.CreatedBy, &task.AssignedTo, &task.ProjectID, &status, &task.ReopenCount, &priority, &task.DueAt, &lateSeconds, &task.ParentID)
+	if err != nil {
+		return taskbus.Task{}, err
+	}
+
+	if task.Status, err = taskbus.ParseStatus(status); err != nil {
+		return taskbus.Task{}, fmt.Errorf("taskID[%d]: %w", task.ID, err)
+	}
+	if task.Priority, err = taskbus.ParsePriority(priority); err != nil {
+		return taskbus.Task{}, fmt.Errorf("taskID[%d]: %w", task.ID, err)
+	}
+	task.LateBy = time.Duration(lateSeconds) * time.Second
+
+	return task, nil
+}
+
+// checkAffected reports ErrNotFound when the statement did not match a row.
+func checkAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("exec: %w", taskbus.ErrNotFound)
+	}
+
+	return nil
+}
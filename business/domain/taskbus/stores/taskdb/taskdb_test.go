@@ -0,0 +1,538 @@
+package taskdb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/taskbus/stores/taskdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	db    *sql.DB
+	mock  sqlmock.Sqlmock
+	store *taskdb.Store
+)
+
+func setupMockDB(t *testing.T) {
+	var err error
+	db, mock, err = sqlmock.New()
+	assert.NoError(t, err)
+
+	store = taskdb.NewStore(db)
+}
+
+func mockTaskRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "title", "description", "created_at", "finished_at", "created_by", "assigned_to", "project_id", "status", "reopen_count", "priority", "due_at", "late_seconds", "parent_id"}).
+		AddRow(1, "Task 1", "Description 1", time.Now(), sql.NullTime{Valid: false}, 1, sql.NullInt32{}, 3, "todo", 0, "medium", nil, 0, nil).
+		AddRow(2, "Task 2", "Description 2", time.Now(), sql.NullTime{Valid: false}, 1, sql.NullInt32{}, 3, "in_progress", 1, "urgent", time.Now(), 0, 1)
+}
+
+func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	dueAt := sql.NullTime{Time: time.Date(2026, 11, 2, 15, 0, 0, 0, time.UTC), Valid: true}
+
+	mock.ExpectExec("INSERT INTO task \\(title, description, created_by, assigned_to, project_id, created_at, finished_at, status, priority, due_at, parent_id\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs("New Task", "This is a new task", 1, sql.NullInt32{Int32: 2, Valid: true}, 3, sqlmock.AnyArg(), sqlmock.AnyArg(), "todo", "high", dueAt, sql.NullInt32{Int32: 7, Valid: true}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	ctx := context.Background()
+	task := taskbus.Task{
+		Title:       "New Task",
+		Status:      taskbus.StatusTodo,
+		Description: "This is a new task",
+		ProjectID:   3,
+		CreatedAt:   time.Now(),
+		CreatedBy:   1,
+		AssignedTo:  sql.NullInt32{Int32: 2, Valid: true},
+		Priority:    taskbus.PriorityHigh,
+		DueAt:       dueAt,
+		ParentID:    sql.NullInt32{Int32: 7, Valid: true},
+	}
+	id, err := store.Create(ctx, task)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assertMockExpectations(t, mock)
+}
+
+func TestQuery(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task ORDER BY id ASC LIMIT \\? OFFSET \\?$").
+		WithArgs(10, 0).
+		WillReturnRows(mockTaskRows())
+
+	ctx := context.Background()
+	tasks, err := store.Query(ctx, taskbus.QueryFilter{}, []order.By{taskbus.DefaultOrderBy}, page.MustParse("1", "10"))
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "Task 1", tasks[0].Title)
+	assert.Equal(t, "Description 1", tasks[0].Description)
+	assert.NotEmpty(t, tasks[0].CreatedAt)
+	assert.True(t, tasks[0].CreatedAt.After(time.Now().Add(-time.Hour)))
+	assert.WithinDuration(t, time.Now(), tasks[0].CreatedAt, time.Minute)
+	assert.False(t, tasks[0].FinishedAt.Valid)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryFilter(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	projectID := 3
+	finished := false
+	title := "milk"
+	filter := taskbus.QueryFilter{ProjectID: &projectID, Finished: &finished, Title: &title}
+	orderBy := []order.By{order.NewBy(taskbus.OrderByCreatedAt, order.DESC)}
+
+	mock.ExpectQuery("^SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task "+
+		"WHERE project_id = \\? AND finished_at IS NULL AND title LIKE \\? ESCAPE '!' ORDER BY created_at DESC, id LIMIT \\? OFFSET \\?$").
+		WithArgs(3, "%milk%", 5, 10).
+		WillReturnRows(mockTaskRows())
+
+	mock.ExpectQuery("^SELECT COUNT\\(1\\) FROM task WHERE project_id = \\? AND finished_at IS NULL AND title LIKE \\? ESCAPE '!'$").
+		WithArgs(3, "%milk%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	ctx := context.Background()
+	tasks, err := store.Query(ctx, filter, orderBy, page.MustParse("3", "5"))
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	count, err := store.Count(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryDueToday(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	now := time.Date(2026, 10, 18, 22, 30, 0, 0, saoPaulo)
+	filter := taskbus.QueryFilter{}.DueToday(now)
+	orderBy := []order.By{order.NewBy(taskbus.OrderByPriority, order.DESC)}
+
+	mock.ExpectQuery("^SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task "+
+		"WHERE status NOT IN \\(\\?, \\?\\) AND due_at >= \\? AND due_at <= \\? "+
+		"ORDER BY CASE priority WHEN 'low' THEN 0 WHEN 'medium' THEN 1 WHEN 'high' THEN 2 ELSE 3 END DESC, id LIMIT \\? OFFSET \\?$").
+		WithArgs("done", "cancelled", time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 2, 59, 59, 0, time.UTC), 10, 0).
+		WillReturnRows(mockTaskRows())
+
+	ctx := context.Background()
+	tasks, err := store.Query(ctx, filter, orderBy, page.MustParse("1", "10"))
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryDatesInUTC(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, saoPaulo)
+	end := time.Date(2026, 10, 18, 21, 0, 0, 0, saoPaulo)
+	filter := taskbus.QueryFilter{StartCreatedDate: &start, EndCreatedDate: &end, StartFinishedDate: &start, EndFinishedDate: &end}
+
+	mock.ExpectQuery("^SELECT COUNT\\(1\\) FROM task WHERE created_at >= \\? AND created_at <= \\? AND finished_at >= \\? AND finished_at <= \\?$").
+		WithArgs(time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := store.Count(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryAfter(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	projectID := 3
+	after := taskbus.Cursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: 7}
+
+	mock.ExpectQuery("^SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task ORDER BY created_at, id LIMIT \\?$").
+		WithArgs(11).
+		WillReturnRows(mockTaskRows())
+
+	mock.ExpectQuery("^SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task "+
+		"WHERE project_id = \\? AND \\(created_at > \\? OR \\(created_at = \\? AND id > \\?\\)\\) ORDER BY created_at, id LIMIT \\?$").
+		WithArgs(3, after.CreatedAt, after.CreatedAt, 7, 11).
+		WillReturnRows(mockTaskRows())
+
+	ctx := context.Background()
+	tasks, err := store.QueryAfter(ctx, taskbus.QueryFilter{}, nil, 11)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	tasks, err = store.QueryAfter(ctx, taskbus.QueryFilter{ProjectID: &projectID}, &after, 11)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryByID(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	dueAt := time.Date(2026, 11, 2, 15, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task WHERE id = ?").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "created_at", "finished_at", "created_by", "assigned_to", "project_id", "status", "reopen_count", "priority", "due_at", "late_seconds", "parent_id"}).
+			AddRow(1, "Task 1", "Description 1", time.Now(), sql.NullTime{}, 1, sql.NullInt32{}, 3, "in_review", 2, "high", dueAt, 5400, 4))
+
+	ctx := context.Background()
+	task, err := store.QueryByID(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Task 1", task.Title)
+	assert.Equal(t, "Description 1", task.Description)
+	assert.Equal(t, 3, task.ProjectID)
+	assert.NotEmpty(t, task.CreatedAt)
+	assert.True(t, task.CreatedAt.After(time.Now().Add(-time.Hour)))
+	assert.False(t, task.FinishedAt.Valid)
+	assert.Equal(t, taskbus.StatusInReview, task.Status)
+	assert.Equal(t, 2, task.ReopenCount)
+	assert.Equal(t, taskbus.PriorityHigh, task.Priority)
+	assert.Equal(t, sql.NullTime{Time: dueAt, Valid: true}, task.DueAt)
+	assert.Equal(t, 90*time.Minute, task.LateBy)
+	assert.Equal(t, sql.NullInt32{Int32: 4, Valid: true}, task.ParentID)
+	assertMockExpectations(t, mock)
+}
+
+func TestUpdate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	finishedAt := sql.NullTime{Time: time.Now(), Valid: true}
+
+	// The finish time only changes through Transition.
+	mock.ExpectExec("^UPDATE task SET title = \\?, description = \\?, assigned_to = \\?, priority = \\?, due_at = \\?, parent_id = \\? WHERE id = \\?$").
+		WithArgs("Update Title", "Update Description", sql.NullInt32{}, "low", sql.NullTime{}, sql.NullInt32{}, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	task := taskbus.Task{ID: 1, Title: "Update Title", Description: "Update Description", FinishedAt: finishedAt, Priority: taskbus.PriorityLow}
+	err := store.Update(ctx, task)
+
+	assert.NoError(t, err)
+	assertMockExpectations(t, mock)
+}
+
+func TestDelete(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM task WHERE id = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	err := store.Delete(ctx, 1)
+
+	assert.NoError(t, err)
+	assertMockExpectations(t, mock)
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM task WHERE id = ?").
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+	err := store.Delete(ctx, 9)
+
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryByIDNotFound(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task WHERE id = ?").
+		WithArgs(9).
+		WillReturnError(sql.ErrNoRows)
+
+	ctx := context.Background()
+	_, err := store.QueryByID(ctx, 9)
+
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
+	assertMockExpectations(t, mock)
+}
+
+func TestTransition(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	finishedAt := sql.NullTime{Time: time.Now(), Valid: true}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE task SET status = \\?, finished_at = \\?, late_seconds = \\?, reopen_count = \\? WHERE id = \\? AND status = \\?$").
+		WithArgs("done", finishedAt, int64(5400), 0, 1, "in_review").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^INSERT INTO task_transition \\(task_id, from_status, to_status, changed_by, changed_at, reason\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)$").
+		WithArgs(1, "in_review", "done", sql.NullInt32{Int32: 2, Valid: true}, finishedAt.Time, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ctx := context.Background()
+	task := taskbus.Task{ID: 1, Status: taskbus.StatusDone, FinishedAt: finishedAt, LateBy: 90 * time.Minute}
+	tr := taskbus.Transition{
+		TaskID:    1,
+		From:      taskbus.StatusInReview,
+		To:        taskbus.StatusDone,
+		ChangedBy: sql.NullInt32{Int32: 2, Valid: true},
+		ChangedAt: finishedAt.Time,
+	}
+
+	assert.NoError(t, store.Transition(ctx, task, tr))
+	assertMockExpectations(t, mock)
+}
+
+func TestTransitionStatusChanged(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE task SET status").
+		WithArgs("in_progress", sql.NullTime{}, int64(0), 0, 1, "todo").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	ctx := context.Background()
+	task := taskbus.Task{ID: 1, Status: taskbus.StatusInProgress}
+	tr := taskbus.Transition{TaskID: 1, From: taskbus.StatusTodo, To: taskbus.StatusInProgress}
+
+	err := store.Transition(ctx, task, tr)
+
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition)
+	assertMockExpectations(t, mock)
+}
+
+func TestTransitionRecordFails(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE task SET status").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^INSERT INTO task_transition").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	ctx := context.Background()
+	task := taskbus.Task{ID: 1, Status: taskbus.StatusInProgress}
+	tr := taskbus.Transition{TaskID: 1, From: taskbus.StatusTodo, To: taskbus.StatusInProgress}
+
+	err := store.Transition(ctx, task, tr)
+
+	assert.ErrorIs(t, err, sql.ErrConnDone, "the status change is rolled back with the record")
+	assertMockExpectations(t, mock)
+}
+
+func TestTransitionAll(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE task SET status").
+		WithArgs("done", sqlmock.AnyArg(), int64(0), 0, 2, "todo").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^INSERT INTO task_transition").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^UPDATE task SET status").
+		WithArgs("done", sqlmock.AnyArg(), int64(0), 0, 1, "todo").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	now := time.Now()
+	tasks := []taskbus.Task{
+		{ID: 2, Status: taskbus.StatusDone, FinishedAt: sql.NullTime{Time: now, Valid: true}},
+		{ID: 1, Status: taskbus.StatusDone, FinishedAt: sql.NullTime{Time: now, Valid: true}},
+	}
+	trs := []taskbus.Transition{
+		{TaskID: 2, From: taskbus.StatusTodo, To: taskbus.StatusDone, ChangedAt: now},
+		{TaskID: 1, From: taskbus.StatusTodo, To: taskbus.StatusDone, ChangedAt: now},
+	}
+
+	err := store.TransitionAll(context.Background(), tasks, trs)
+
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition, "the subtask already moved is rolled back")
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryChildren(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id, status, reopen_count, priority, due_at, late_seconds, parent_id FROM task WHERE parent_id = \\? ORDER BY id$").
+		WithArgs(1).
+		WillReturnRows(mockTaskRows())
+
+	ctx := context.Background()
+	tasks, err := store.QueryChildren(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryProgress(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT parent_id, SUM\\(CASE WHEN status = \\? THEN 1 ELSE 0 END\\), COUNT\\(1\\) FROM task "+
+		"WHERE status <> \\? AND parent_id IN \\(\\?, \\?\\) GROUP BY parent_id$").
+		WithArgs("done", "cancelled", 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id", "done", "total"}).AddRow(1, 2, 3))
+
+	ctx := context.Background()
+	progress, err := store.QueryProgress(ctx, []int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]taskbus.Progress{1: {Done: 2, Total: 3}}, progress)
+	assertMockExpectations(t, mock)
+}
+
+func TestAddDependency(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^INSERT INTO task_dependency \\(task_id, blocker_id, created_at\\) VALUES \\(\\?, \\?, \\?\\)$").
+		WithArgs(2, 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO task_dependency").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '2-1' for key 'PRIMARY'"})
+
+	ctx := context.Background()
+	dep := taskbus.Dependency{TaskID: 2, BlockerID: 1, CreatedAt: time.Now()}
+
+	assert.NoError(t, store.AddDependency(ctx, dep))
+	assert.ErrorIs(t, store.AddDependency(ctx, dep), taskbus.ErrDependencyExists)
+	assertMockExpectations(t, mock)
+}
+
+func TestRemoveDependencyNotFound(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^DELETE FROM task_dependency WHERE task_id = \\? AND blocker_id = \\?$").
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+	err := store.RemoveDependency(ctx, 2, 1)
+
+	assert.ErrorIs(t, err, taskbus.ErrDependencyNotFound)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryBlockers(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT t.id, .+, t.parent_id FROM task t JOIN task_dependency d ON d.blocker_id = t.id WHERE d.task_id = \\? ORDER BY t.id$").
+		WithArgs(5).
+		WillReturnRows(mockTaskRows())
+
+	ctx := context.Background()
+	tasks, err := store.QueryBlockers(ctx, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryDependents(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT t.id, .+, t.parent_id FROM task t JOIN task_dependency d ON d.task_id = t.id WHERE d.blocker_id = \\? ORDER BY t.id$").
+		WithArgs(5).
+		WillReturnRows(mockTaskRows())
+
+	ctx := context.Background()
+	tasks, err := store.QueryDependents(ctx, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryOpenDependencies(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"task_id", "blocker_id", "created_at"}).
+		AddRow(2, 1, time.Now()).
+		AddRow(3, 2, time.Now())
+
+	mock.ExpectQuery("^SELECT d.task_id, d.blocker_id, d.created_at FROM task_dependency d "+
+		"JOIN task t ON t.id = d.task_id JOIN task b ON b.id = d.blocker_id "+
+		"WHERE t.project_id = \\? AND b.project_id = \\? AND t.status NOT IN \\(\\?, \\?\\) AND b.status NOT IN \\(\\?, \\?\\) "+
+		"ORDER BY d.task_id, d.blocker_id$").
+		WithArgs(1, 1, "done", "cancelled", "done", "cancelled").
+		WillReturnRows(rows)
+
+	ctx := context.Background()
+	deps, err := store.QueryOpenDependencies(ctx, 1)
+
+	assert.NoError(t, err)
+	if assert.Len(t, deps, 2) {
+		assert.Equal(t, 3, deps[1].TaskID)
+		assert.Equal(t, 2, deps[1].BlockerID)
+	}
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryTransitions(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "task_id", "from_status", "to_status", "changed_by", "changed_at", "reason"}).
+		AddRow(1, 1, "done", "todo", 2, time.Now(), "missed a step").
+		AddRow(2, 1, "todo", "done", nil, time.Now(), "")
+
+	mock.ExpectQuery("^SELECT id, task_id, from_status, to_status, changed_by, changed_at, reason FROM task_transition WHERE task_id = \\? ORDER BY changed_at, id$").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	ctx := context.Background()
+	trs, err := store.QueryTransitions(ctx, 1)
+
+	assert.NoError(t, err)
+	if assert.Len(t, trs, 2) {
+		assert.Equal(t, taskbus.StatusTodo, trs[0].To)
+		assert.Equal(t, int32(2), trs[0].ChangedBy.Int32)
+		assert.Equal(t, "missed a step", trs[0].Reason)
+		assert.False(t, trs[1].ChangedBy.Valid)
+	}
+	assertMockExpectations(t, mock)
+}
@@ -0,0 +1,77 @@
+package taskmem
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"strings"
+)
+
+// match reports whether the task satisfies every field set in the filter,
+// mirroring the WHERE clause the SQL store builds.
+func match(filter taskbus.QueryFilter, task taskbus.Task) bool {
+	if filter.ProjectID != nil && task.ProjectID != *filter.ProjectID {
+		return false
+	}
+
+	if filter.AssignedTo != nil && (!task.AssignedTo.Valid || int(task.AssignedTo.Int32) != *filter.AssignedTo) {
+		return false
+	}
+
+	if filter.CreatedBy != nil && task.CreatedBy != *filter.CreatedBy {
+		return false
+	}
+
+	if filter.ParentID != nil && (!task.ParentID.Valid || int(task.ParentID.Int32) != *filter.ParentID) {
+		return false
+	}
+
+	if filter.Finished != nil && task.FinishedAt.Valid != *filter.Finished {
+		return false
+	}
+
+	if filter.Status != nil && task.Status != *filter.Status {
+		return false
+	}
+
+	if filter.Open != nil && open(task) != *filter.Open {
+		return false
+	}
+
+	if filter.Priority != nil && task.Priority != *filter.Priority {
+		return false
+	}
+
+	if filter.StartCreatedDate != nil && task.CreatedAt.Before(*filter.StartCreatedDate) {
+		return false
+	}
+
+	if filter.EndCreatedDate != nil && task.CreatedAt.After(*filter.EndCreatedDate) {
+		return false
+	}
+
+	if filter.StartFinishedDate != nil && (!task.FinishedAt.Valid || task.FinishedAt.Time.Before(*filter.StartFinishedDate)) {
+		return false
+	}
+
+	if filter.EndFinishedDate != nil && (!task.FinishedAt.Valid || task.FinishedAt.Time.After(*filter.EndFinishedDate)) {
+		return false
+	}
+
+	if filter.StartDueDate != nil && (!task.DueAt.Valid || task.DueAt.Time.Before(*filter.StartDueDate)) {
+		return false
+	}
+
+	if filter.EndDueDate != nil && (!task.DueAt.Valid || task.DueAt.Time.After(*filter.EndDueDate)) {
+		return false
+	}
+
+	if filter.Title != nil && !strings.Contains(strings.ToLower(task.Title), strings.ToLower(*filter.Title)) {
+		return false
+	}
+
+	return true
+}
+
+// open reports whether the task is neither done nor cancelled.
+func open(task taskbus.Task) bool {
+	return task.Status != taskbus.StatusDone && task.Status != taskbus.StatusCancelled
+}
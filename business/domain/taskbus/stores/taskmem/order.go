@@ -0,0 +1,105 @@
+package taskmem
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/sdk/order"
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+var orderByFields = map[string]func(a, b taskbus.Task) int{
+	taskbus.OrderByID: func(a, b taskbus.Task) int {
+		return cmp.Compare(a.ID, b.ID)
+	},
+	taskbus.OrderByTitle: func(a, b taskbus.Task) int {
+		return strings.Compare(a.Title, b.Title)
+	},
+	taskbus.OrderByProjectID: func(a, b taskbus.Task) int {
+		return cmp.Compare(a.ProjectID, b.ProjectID)
+	},
+	taskbus.OrderByCreatedAt: func(a, b taskbus.Task) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	},
+	taskbus.OrderByFinishedAt: func(a, b taskbus.Task) int {
+		return compareNull(a.FinishedAt.Valid, b.FinishedAt.Valid, func() int {
+			return a.FinishedAt.Time.Compare(b.FinishedAt.Time)
+		})
+	},
+	taskbus.OrderByCreatedBy: func(a, b taskbus.Task) int {
+		return cmp.Compare(a.CreatedBy, b.CreatedBy)
+	},
+	taskbus.OrderByAssignedTo: func(a, b taskbus.Task) int {
+		return compareNull(a.AssignedTo.Valid, b.AssignedTo.Valid, func() int {
+			return cmp.Compare(a.AssignedTo.Int32, b.AssignedTo.Int32)
+		})
+	},
+	taskbus.OrderByReopenCount: func(a, b taskbus.Task) int {
+		return cmp.Compare(a.ReopenCount, b.ReopenCount)
+	},
+	taskbus.OrderByPriority: func(a, b taskbus.Task) int {
+		return a.Priority.Compare(b.Priority)
+	},
+	taskbus.OrderByDueAt: func(a, b taskbus.Task) int {
+		return compareNull(a.DueAt.Valid, b.DueAt.Valid, func() int {
+			return a.DueAt.Time.Compare(b.DueAt.Time)
+		})
+	},
+}
+
+// compareTasks returns the comparison function for the list. Like the SQL
+// store, the id breaks the ties so the pages are stable.
+func compareTasks(orderBy []order.By) (func(a, b taskbus.Task) int, error) {
+	type key struct {
+		compare func(a, b taskbus.Task) int
+		desc    bool
+	}
+
+	keys := make([]key, 0, len(orderBy)+1)
+	for _, by := range orderBy {
+		compare, exists := orderByFields[by.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q does not exist", by.Field)
+		}
+		keys = append(keys, key{compare: compare, desc: by.Direction == order.DESC})
+	}
+	keys = append(keys, key{compare: orderByFields[taskbus.OrderByID]})
+
+	f := func(a, b taskbus.Task) int {
+		for _, k := range keys {
+			c := k.compare(a, b)
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	return f, nil
+}
+
+// compareCursor orders the tasks by (created_at, id), the keyset order of
+// QueryAfter.
+func compareCursor(a, b taskbus.Task) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// compareNull sorts NULL values first, the way MySQL and SQLite do in
+// ascending order.
+func compareNull(aValid bool, bValid bool, compare func() int) int {
+	switch {
+	case aValid && bValid:
+		return compare()
+	case aValid:
+		return 1
+	case bValid:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,421 @@
+// Package taskmem contains task related CRUD functionality held in memory.
+package taskmem
+
+import (
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"context"
+	"fmt"
+	"slices"
+)
+
+const (
+	table           = "task"
+	transitionTable = "task_transition"
+	dependencyTable = "task_dependency"
+)
+
+// Store manages the set of APIs for task in-memory access.
+type Store struct {
+	db *memdb.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *memdb.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new task and returns its ID. The project, creator and
+// assignee must exist.
+func (s *Store) Create(ctx context.Context, task taskbus.Task) (int, error) {
+	var id int
+	err := s.db.Update(func(tx *memdb.Tx) error {
+		var err error
+		id, err = tx.Insert(table, task, refs(task)...)
+		return err
+	})
+
+	return id, err
+}
+
+// Update stores the editable fields of a task. The status, finish time,
+// lateness and reopen count are left to Transition, so a transition made
+// since the task was read is kept.
+func (s *Store) Update(ctx context.Context, task taskbus.Task) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		v, exists := tx.Get(table, task.ID)
+		if !exists {
+			return taskbus.ErrNotFound
+		}
+
+		stored := toBusTask(task.ID, v)
+		stored.Title = task.Title
+		stored.Description = task.Description
+		stored.AssignedTo = task.AssignedTo
+		stored.Priority = task.Priority
+		stored.DueAt = task.DueAt
+		stored.ParentID = task.ParentID
+
+		_, err := tx.Replace(table, task.ID, stored, refs(stored)...)
+		return err
+	})
+}
+
+// Delete removes a task, its transitions and its dependencies by its ID.
+func (s *Store) Delete(ctx context.Context, id int) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		if _, exists := tx.Get(table, id); !exists {
+			return taskbus.ErrNotFound
+		}
+
+		for _, r := range tx.Rows(dependencyTable) {
+			if dep := toBusDependency(r.Value); dep.TaskID != id && dep.BlockerID != id {
+				continue
+			}
+			if _, err := tx.Delete(dependencyTable, r.ID); err != nil {
+				return err
+			}
+		}
+
+		for _, r := range tx.Rows(transitionTable) {
+			if toBusTransition(r.ID, r.Value).TaskID != id {
+				continue
+			}
+			if _, err := tx.Delete(transitionTable, r.ID); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Delete(table, id)
+		return err
+	})
+}
+
+// Query retrieves a page of the tasks matching the filter.
+func (s *Store) Query(ctx context.Context, filter taskbus.QueryFilter, orderBy []order.By, page page.Page) ([]taskbus.Task, error) {
+	compare, err := compareTasks(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := s.filter(filter)
+	slices.SortFunc(tasks, compare)
+
+	start := min(page.Offset(), len(tasks))
+	end := min(start+page.RowsPerPage(), len(tasks))
+
+	return tasks[start:end], nil
+}
+
+// QueryAfter retrieves up to limit tasks matching the filter that come after
+// the cursor in (created_at, id) order.
+func (s *Store) QueryAfter(ctx context.Context, filter taskbus.QueryFilter, after *taskbus.Cursor, limit int) ([]taskbus.Task, error) {
+	tasks := s.filter(filter)
+	slices.SortFunc(tasks, compareCursor)
+
+	start := 0
+	if after != nil {
+		mark := taskbus.Task{CreatedAt: after.CreatedAt, ID: after.ID}
+		start = slices.IndexFunc(tasks, func(task taskbus.Task) bool {
+			return compareCursor(task, mark) > 0
+		})
+		if start == -1 {
+			start = len(tasks)
+		}
+	}
+	end := min(start+limit, len(tasks))
+
+	return tasks[start:end], nil
+}
+
+// Count returns the total number of tasks matching the filter.
+func (s *Store) Count(ctx context.Context, filter taskbus.QueryFilter) (int, error) {
+	return len(s.filter(filter)), nil
+}
+
+// QueryByID retrieves a task by its ID.
+func (s *Store) QueryByID(ctx context.Context, id int) (taskbus.Task, error) {
+	var task taskbus.Task
+	err := s.db.View(func(tx *memdb.Tx) error {
+		v, exists := tx.Get(table, id)
+		if !exists {
+			return taskbus.ErrNotFound
+		}
+
+		task = toBusTask(id, v)
+		return nil
+	})
+
+	return task, err
+}
+
+// Transition stores the new status, finish time, lateness and reopen count of
+// the task, provided it still has the status the transition starts from, and
+// records the transition.
+func (s *Store) Transition(ctx context.Context, task taskbus.Task, tr taskbus.Transition) error {
+	return s.TransitionAll(ctx, []taskbus.Task{task}, []taskbus.Transition{tr})
+}
+
+// TransitionAll does what Transition does for each task and the transition
+// at the same index, either moving every task or none.
+func (s *Store) TransitionAll(ctx context.Context, tasks []taskbus.Task, trs []taskbus.Transition) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		for i, task := range tasks {
+			if err := transition(tx, task, trs[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func transition(tx *memdb.Tx, task taskbus.Task, tr taskbus.Transition) error {
+	v, exists := tx.Get(table, task.ID)
+	if !exists {
+		return taskbus.ErrNotFound
+	}
+
+	stored := toBusTask(task.ID, v)
+	if stored.Status != tr.From {
+		return fmt.Errorf("taskID[%d] is no longer %s: %w", task.ID, tr.From, taskbus.ErrInvalidTransition)
+	}
+
+	stored.Status = task.Status
+	stored.FinishedAt = task.FinishedAt
+	stored.LateBy = task.LateBy
+	stored.ReopenCount = task.ReopenCount
+
+	if _, err := tx.Replace(table, task.ID, stored, refs(stored)...); err != nil {
+		return err
+	}
+
+	_, err := tx.Insert(transitionTable, tr, transitionRefs(tr)...)
+	return err
+}
+
+// QueryTransitions retrieves the status changes of a task, oldest first.
+func (s *Store) QueryTransitions(ctx context.Context, taskID int) ([]taskbus.Transition, error) {
+	var trs []taskbus.Transition
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(transitionTable) {
+			if tr := toBusTransition(r.ID, r.Value); tr.TaskID == taskID {
+				trs = append(trs, tr)
+			}
+		}
+		return nil
+	})
+
+	return trs, nil
+}
+
+// QueryChildren retrieves the direct subtasks of a task in id order.
+func (s *Store) QueryChildren(ctx context.Context, parentID int) ([]taskbus.Task, error) {
+	return s.filter(taskbus.QueryFilter{ParentID: &parentID}), nil
+}
+
+// QueryProgress counts the done and the total direct subtasks of each task,
+// leaving the cancelled ones out.
+func (s *Store) QueryProgress(ctx context.Context, parentIDs []int) (map[int]taskbus.Progress, error) {
+	progress := make(map[int]taskbus.Progress)
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			task := toBusTask(r.ID, r.Value)
+			if !task.ParentID.Valid || task.Status == taskbus.StatusCancelled {
+				continue
+			}
+
+			parentID := int(task.ParentID.Int32)
+			if !slices.Contains(parentIDs, parentID) {
+				continue
+			}
+
+			p := progress[parentID]
+			p.Total++
+			if task.Status == taskbus.StatusDone {
+				p.Done++
+			}
+			progress[parentID] = p
+		}
+		return nil
+	})
+
+	return progress, nil
+}
+
+// AddDependency records that a task is blocked by another one. Both tasks
+// must exist.
+func (s *Store) AddDependency(ctx context.Context, dep taskbus.Dependency) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		if _, exists := findDependency(tx, dep.TaskID, dep.BlockerID); exists {
+			return taskbus.ErrDependencyExists
+		}
+
+		_, err := tx.Insert(dependencyTable, dep, dependencyRefs(dep)...)
+		return err
+	})
+}
+
+// RemoveDependency removes the link between a task and its blocker.
+func (s *Store) RemoveDependency(ctx context.Context, taskID int, blockerID int) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		id, exists := findDependency(tx, taskID, blockerID)
+		if !exists {
+			return taskbus.ErrDependencyNotFound
+		}
+
+		_, err := tx.Delete(dependencyTable, id)
+		return err
+	})
+}
+
+// QueryBlockers retrieves the tasks blocking a task in id order.
+func (s *Store) QueryBlockers(ctx context.Context, taskID int) ([]taskbus.Task, error) {
+	var tasks []taskbus.Task
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(dependencyTable) {
+			dep := toBusDependency(r.Value)
+			if dep.TaskID != taskID {
+				continue
+			}
+
+			if v, exists := tx.Get(table, dep.BlockerID); exists {
+				tasks = append(tasks, toBusTask(dep.BlockerID, v))
+			}
+		}
+		return nil
+	})
+
+	slices.SortFunc(tasks, func(a, b taskbus.Task) int {
+		return a.ID - b.ID
+	})
+
+	return tasks, nil
+}
+
+// QueryDependents retrieves the tasks blocked by a task in id order.
+func (s *Store) QueryDependents(ctx context.Context, blockerID int) ([]taskbus.Task, error) {
+	var tasks []taskbus.Task
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(dependencyTable) {
+			dep := toBusDependency(r.Value)
+			if dep.BlockerID != blockerID {
+				continue
+			}
+
+			if v, exists := tx.Get(table, dep.TaskID); exists {
+				tasks = append(tasks, toBusTask(dep.TaskID, v))
+			}
+		}
+		return nil
+	})
+
+	slices.SortFunc(tasks, func(a, b taskbus.Task) int {
+		return a.ID - b.ID
+	})
+
+	return tasks, nil
+}
+
+// QueryOpenDependencies retrieves the links between the tasks of a project
+// that are neither done nor cancelled.
+func (s *Store) QueryOpenDependencies(ctx context.Context, projectID int) ([]taskbus.Dependency, error) {
+	var deps []taskbus.Dependency
+	s.db.View(func(tx *memdb.Tx) error {
+		open := func(id int) bool {
+			v, exists := tx.Get(table, id)
+			if !exists {
+				return false
+			}
+
+			task := toBusTask(id, v)
+			return task.ProjectID == projectID && task.Status != taskbus.StatusDone && task.Status != taskbus.StatusCancelled
+		}
+
+		for _, r := range tx.Rows(dependencyTable) {
+			if dep := toBusDependency(r.Value); open(dep.TaskID) && open(dep.BlockerID) {
+				deps = append(deps, dep)
+			}
+		}
+		return nil
+	})
+
+	return deps, nil
+}
+
+// filter returns the tasks matching the filter in id order.
+func (s *Store) filter(filter taskbus.QueryFilter) []taskbus.Task {
+	var tasks []taskbus.Task
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			if task := toBusTask(r.ID, r.Value); match(filter, task) {
+				tasks = append(tasks, task)
+			}
+		}
+		return nil
+	})
+
+	return tasks
+}
+
+func refs(task taskbus.Task) []memdb.Ref {
+	refs := []memdb.Ref{
+		{Table: "project", ID: task.ProjectID},
+		{Table: "users", ID: task.CreatedBy},
+	}
+
+	if task.AssignedTo.Valid {
+		refs = append(refs, memdb.Ref{Table: "users", ID: int(task.AssignedTo.Int32)})
+	}
+
+	if task.ParentID.Valid {
+		refs = append(refs, memdb.Ref{Table: table, ID: int(task.ParentID.Int32)})
+	}
+
+	return refs
+}
+
+func transitionRefs(tr taskbus.Transition) []memdb.Ref {
+	refs := []memdb.Ref{{Table: table, ID: tr.TaskID}}
+
+	if tr.ChangedBy.Valid {
+		refs = append(refs, memdb.Ref{Table: "users", ID: int(tr.ChangedBy.Int32)})
+	}
+
+	return refs
+}
+
+func findDependency(tx *memdb.Tx, taskID int, blockerID int) (int, bool) {
+	for _, r := range tx.Rows(dependencyTable) {
+		if dep := toBusDependency(r.Value); dep.TaskID == taskID && dep.BlockerID == blockerID {
+			return r.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+func dependencyRefs(dep taskbus.Dependency) []memdb.Ref {
+	return []memdb.Ref{
+		{Table: table, ID: dep.TaskID},
+		{Table: table, ID: dep.BlockerID},
+	}
+}
+
+func toBusDependency(v any) taskbus.Dependency {
+	return v.(taskbus.Dependency)
+}
+
+func toBusTransition(id int, v any) taskbus.Transition {
+	tr := v.(taskbus.Transition)
+	tr.ID = id
+	return tr
+}
+
+func toBusTask(id int, v any) taskbus.Task {
+	task := v.(taskbus.Task)
+	task.ID = id
+	return task
+}
@@ -3,25 +3,74 @@ package taskbus
 import (
 	"TODO-list/business/domain/projectbus"
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"TODO-list/foundation/metrics"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound           = errors.New("task not found")
+	ErrAssigneeNotMember  = errors.New("assigned user is not a project member")
+	ErrInvalidTransition  = errors.New("invalid status transition")
+	ErrReasonRequired     = errors.New("a reason is required")
+	ErrInvalidParent      = errors.New("invalid parent task")
+	ErrParentCycle        = errors.New("parent task would create a cycle")
+	ErrOpenSubtasks       = errors.New("task has open subtasks")
+	ErrHasSubtasks        = errors.New("task has subtasks")
+	ErrInvalidBlocker     = errors.New("invalid blocker task")
+	ErrDependencyExists   = errors.New("dependency already exists")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrOpenBlockers       = errors.New("task has open blockers")
+	ErrBlocksDoneTasks    = errors.New("task blocks done tasks")
+)
 
-	_ "github.com/go-sql-driver/mysql"
+var (
+	tasksCreated  = metrics.NewCounter("tasks_created_total", "Number of tasks created.")
+	tasksFinished = metrics.NewCounter("tasks_finished_total", "Number of tasks finished.")
+	tasksReopened = metrics.NewCounter("tasks_reopened_total", "Number of finished tasks reopened.")
 )
 
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, task Task) (int, error)
+	Update(ctx context.Context, task Task) error
+	Delete(ctx context.Context, id int) error
+	Query(ctx context.Context, filter QueryFilter, orderBy []order.By, page page.Page) ([]Task, error)
+	QueryAfter(ctx context.Context, filter QueryFilter, after *Cursor, limit int) ([]Task, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, id int) (Task, error)
+	Transition(ctx context.Context, task Task, tr Transition) error
+	TransitionAll(ctx context.Context, tasks []Task, trs []Transition) error
+	QueryTransitions(ctx context.Context, taskID int) ([]Transition, error)
+	QueryChildren(ctx context.Context, parentID int) ([]Task, error)
+	QueryProgress(ctx context.Context, parentIDs []int) (map[int]Progress, error)
+	AddDependency(ctx context.Context, dep Dependency) error
+	RemoveDependency(ctx context.Context, taskID int, blockerID int) error
+	QueryBlockers(ctx context.Context, taskID int) ([]Task, error)
+	QueryDependents(ctx context.Context, blockerID int) ([]Task, error)
+	QueryOpenDependencies(ctx context.Context, projectID int) ([]Dependency, error)
+}
+
 // Business handles business logic and persistence of tasks.
 type Business struct {
-	db         *sql.DB
+	storer     Storer
 	userBus    *userbus.Business
 	projectBus *projectbus.Business
 }
 
-// NewBusiness initializes a new instance of Business with the given database and user business logic.
-func NewBusiness(db *sql.DB, userBus *userbus.Business, projectBus *projectbus.Business) *Business {
+// NewBusiness initializes a new instance of Business with the given storer and user business logic.
+func NewBusiness(storer Storer, userBus *userbus.Business, projectBus *projectbus.Business) *Business {
 	return &Business{
-		db:         db,
+		storer:     storer,
 		userBus:    userBus,
 		projectBus: projectBus,
 	}
@@ -34,125 +83,511 @@ func (s *Business) Create(ctx context.Context, nt NewTask) (Task, error) {
 		return Task{}, fmt.Errorf("project with ID %d does not exist: %w", nt.ProjectID, err)
 	}
 	if !project.Active {
-		return Task{}, fmt.Errorf("project with ID %d is not active", nt.ProjectID)
+		return Task{}, fmt.Errorf("projectID[%d]: %w", nt.ProjectID, projectbus.ErrProjectInactive)
 	}
 
 	creator, err := s.userBus.QueryById(ctx, nt.CreatedBy)
 	if err != nil {
-		return Task{}, fmt.Errorf("failed to retrieve creator user with ID %d: %v", nt.CreatedBy, err)
+		return Task{}, fmt.Errorf("failed to retrieve creator user with ID %d: %w", nt.CreatedBy, err)
 	}
 	if !creator.Active {
-		return Task{}, fmt.Errorf("creator user with ID %d is not active", nt.CreatedBy)
+		return Task{}, fmt.Errorf("creator userID[%d]: %w", nt.CreatedBy, userbus.ErrUserInactive)
 	}
 
-	if nt.AssignedTo.Valid {
-		user, err := s.userBus.QueryById(ctx, int(nt.AssignedTo.Int32))
-		if err != nil {
-			return Task{}, fmt.Errorf("failed to retrieve assigned user with ID %d: %v", nt.AssignedTo.Int32, err)
-		}
-		if !user.Active {
-			return Task{}, fmt.Errorf("assigned user with ID %d is not active", nt.AssignedTo.Int32)
-		}
+	if err := s.checkAssignee(ctx, nt.ProjectID, nt.AssignedTo); err != nil {
+		return Task{}, err
 	}
 
-	createdAt := sql.NullTime{Time: time.Now(), Valid: true}
-	finishedAt := sql.NullTime{Valid: false}
-
-	query := "INSERT INTO task (title, description, created_by, assigned_to, project_id, created_at, finished_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
-	result, err := s.db.ExecContext(ctx, query, nt.Title, nt.Description, nt.CreatedBy, nt.AssignedTo, nt.ProjectID, createdAt, finishedAt)
-	if err != nil {
+	if err := s.checkParent(ctx, 0, nt.ProjectID, nt.ParentID); err != nil {
 		return Task{}, err
 	}
 
-	lastInsertID, err := result.LastInsertId()
-	if err != nil {
-		return Task{}, err
+	priority := nt.Priority
+	if priority == (Priority{}) {
+		priority = PriorityMedium
 	}
 
-	return Task{
-		ID:          int(lastInsertID),
+	task := Task{
 		Title:       nt.Title,
 		Description: nt.Description,
 		ProjectID:   nt.ProjectID,
-		CreatedAt:   createdAt.Time,
-		FinishedAt:  finishedAt,
+		CreatedAt:   nowUTC(),
+		FinishedAt:  sql.NullTime{Valid: false},
 		CreatedBy:   nt.CreatedBy,
 		AssignedTo:  nt.AssignedTo,
-	}, nil
-}
-
-// Query retrieves all tasks from the database.
-func (s *Business) Query(ctx context.Context) ([]Task, error) {
-	query := "SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id FROM task"
+		Status:      StatusTodo,
+		Priority:    priority,
+		DueAt:       dueAt(nt.DueAt),
+		ParentID:    nt.ParentID,
+	}
 
-	rows, err := s.db.QueryContext(ctx, query)
+	id, err := s.storer.Create(ctx, task)
 	if err != nil {
-		return nil, err
+		return Task{}, fmt.Errorf("create: %w", err)
 	}
-	defer rows.Close()
+	task.ID = id
 
-	var tasks []Task
-	for rows.Next() {
-		var task Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.CreatedAt, &task.FinishedAt, &task.CreatedBy, &task.AssignedTo, &task.ProjectID)
-		if err != nil {
-			return nil, err
-		}
-		tasks = append(tasks, task)
+	tasksCreated.Inc()
+
+	return task, nil
+}
+
+// Query retrieves a page of the tasks matching the filter in the given order.
+func (s *Business) Query(ctx context.Context, filter QueryFilter, orderBy []order.By, page page.Page) ([]Task, error) {
+	tasks, err := s.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return tasks, nil
+}
+
+// QueryAfter retrieves up to limit tasks matching the filter that come after
+// the cursor in (created_at, id) order. A nil cursor starts from the first
+// task.
+func (s *Business) QueryAfter(ctx context.Context, filter QueryFilter, after *Cursor, limit int) ([]Task, error) {
+	tasks, err := s.storer.QueryAfter(ctx, filter, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query after: %w", err)
 	}
 
 	return tasks, nil
 }
 
+// Count returns the total number of tasks matching the filter.
+func (s *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	n, err := s.storer.Count(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+
+	return n, nil
+}
+
 // QueryByID retrieves a task by its ID.
 func (s *Business) QueryByID(ctx context.Context, id int) (Task, error) {
-	query := "SELECT id, title, description, project_id, created_at, finished_at, created_by, assigned_to FROM task WHERE id = ?"
-	row := s.db.QueryRowContext(ctx, query, id)
-
-	var task Task
-	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.CreatedAt, &task.FinishedAt, &task.CreatedBy, &task.AssignedTo)
+	task, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return Task{}, err
+		return Task{}, fmt.Errorf("query: taskID[%d]: %w", id, err)
 	}
 
 	return task, nil
 }
 
-// Update modifies task information in the database and returns the updated task.
+// Update modifies task information in the database. An empty priority keeps
+// the current one.
 func (s *Business) Update(ctx context.Context, id int, ut UpdateTask) error {
-	query := "UPDATE task SET title = ?, description = ?, assigned_to = ? WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, ut.Title, ut.Description, ut.AssignedTo, id)
+	task, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("query: taskID[%d]: %w", id, err)
+	}
+
+	// A task keeps its assignee when the user later leaves the project, so
+	// only a new assignee is checked.
+	if ut.AssignedTo != task.AssignedTo {
+		if err := s.checkAssignee(ctx, task.ProjectID, ut.AssignedTo); err != nil {
+			return err
+		}
+	}
+
+	if ut.ParentID != task.ParentID {
+		if err := s.checkParent(ctx, task.ID, task.ProjectID, ut.ParentID); err != nil {
+			return err
+		}
+	}
+
+	task.Title = ut.Title
+	task.Description = ut.Description
+	task.AssignedTo = ut.AssignedTo
+	task.ParentID = ut.ParentID
+	task.DueAt = dueAt(ut.DueAt)
+	if ut.Priority != (Priority{}) {
+		task.Priority = ut.Priority
+	}
+
+	if err := s.storer.Update(ctx, task); err != nil {
+		return fmt.Errorf("update: %w", err)
 	}
 
 	return nil
 }
 
-// Delete removes a task from the database by its ID.
+// Delete removes a task from the database by its ID. A task with subtasks
+// can not be deleted.
 func (s *Business) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM task WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, id)
-	return err
+	children, err := s.storer.QueryChildren(ctx, id)
+	if err != nil {
+		return fmt.Errorf("query children: taskID[%d]: %w", id, err)
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("taskID[%d]: %w", id, ErrHasSubtasks)
+	}
+
+	if err := s.storer.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: taskID[%d]: %w", id, err)
+	}
+
+	return nil
+}
+
+// Finish moves a task to done. A task with open subtasks or open blockers
+// can not be finished.
+func (s *Business) Finish(ctx context.Context, id int, changedBy sql.NullInt32) error {
+	if _, err := s.Transition(ctx, id, StatusDone, changedBy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FinishWithSubtasks moves a task to done along with every open subtask
+// below it. Nothing is finished unless all of them can move to done and
+// none of them is blocked by an open task left out of the finish, and the
+// store moves them all in one transaction.
+func (s *Business) FinishWithSubtasks(ctx context.Context, id int, changedBy sql.NullInt32) error {
+	task, err := s.storer.QueryByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("query: taskID[%d]: %w", id, err)
+	}
+
+	if !task.Status.CanTransition(StatusDone) {
+		return fmt.Errorf("taskID[%d] from[%s] to[%s]: %w", id, task.Status, StatusDone, ErrInvalidTransition)
+	}
+
+	subtasks, err := s.openSubtasks(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subtasks {
+		if !sub.Status.CanTransition(StatusDone) {
+			return fmt.Errorf("subtaskID[%d] from[%s] to[%s]: %w", sub.ID, sub.Status, StatusDone, ErrInvalidTransition)
+		}
+	}
+
+	finishing := map[int]bool{id: true}
+	for _, sub := range subtasks {
+		finishing[sub.ID] = true
+	}
+
+	for _, t := range append([]Task{task}, subtasks...) {
+		blockers, err := s.openBlockers(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, blocker := range blockers {
+			if !finishing[blocker.ID] {
+				return fmt.Errorf("taskID[%d] blocker taskID[%d]: %w", t.ID, blocker.ID, ErrOpenBlockers)
+			}
+		}
+	}
+
+	// The subtasks come parents first, so walking them backwards records
+	// every task as finished after its own subtasks.
+	now := nowUTC()
+	tasks := make([]Task, 0, len(subtasks)+1)
+	trs := make([]Transition, 0, len(subtasks)+1)
+	finish := func(t Task) {
+		t, tr := changeStatus(t, StatusDone, changedBy, "", now)
+		tasks = append(tasks, t)
+		trs = append(trs, tr)
+	}
+	for i := len(subtasks) - 1; i >= 0; i-- {
+		finish(subtasks[i])
+	}
+	finish(task)
+
+	if err := s.storer.TransitionAll(ctx, tasks, trs); err != nil {
+		return fmt.Errorf("transition: taskID[%d] with subtasks: %w", id, err)
+	}
+
+	tasksFinished.Add(float64(len(tasks)))
+
+	return nil
+}
+
+// Transition moves a task to another status when the workflow allows it and
+// records when and by whom. Moving to done sets the finish time and needs
+// every subtask and every blocker to be done or cancelled. Subtasks are
+// checked all the way down, as a cancelled subtask may have open ones below.
+func (s *Business) Transition(ctx context.Context, id int, to Status, changedBy sql.NullInt32) (Task, error) {
+	task, err := s.storer.QueryByID(ctx, id)
+	if err != nil {
+		return Task{}, fmt.Errorf("query: taskID[%d]: %w", id, err)
+	}
+
+	if !task.Status.CanTransition(to) {
+		return Task{}, fmt.Errorf("taskID[%d] from[%s] to[%s]: %w", id, task.Status, to, ErrInvalidTransition)
+	}
+
+	if to == StatusDone {
+		subtasks, err := s.openSubtasks(ctx, id)
+		if err != nil {
+			return Task{}, err
+		}
+		if len(subtasks) > 0 {
+			return Task{}, fmt.Errorf("taskID[%d] subtaskID[%d] is %s: %w", id, subtasks[0].ID, subtasks[0].Status, ErrOpenSubtasks)
+		}
+
+		blockers, err := s.openBlockers(ctx, id)
+		if err != nil {
+			return Task{}, err
+		}
+		if len(blockers) > 0 {
+			return Task{}, fmt.Errorf("taskID[%d] blocker taskID[%d]: %w", id, blockers[0].ID, ErrOpenBlockers)
+		}
+	}
+
+	task, err = s.transition(ctx, task, to, changedBy, "")
+	if err != nil {
+		return Task{}, err
+	}
+
+	if to == StatusDone {
+		tasksFinished.Inc()
+	}
+
+	return task, nil
+}
+
+// Reopen moves a finished task back to todo, clearing its finish time. The
+// reason is recorded with the transition and the task counts the reopening.
+// A subtask of a done parent, or a blocker of a done task, can not be
+// reopened.
+func (s *Business) Reopen(ctx context.Context, id int, reason string, changedBy sql.NullInt32) (Task, error) {
+	if strings.TrimSpace(reason) == "" {
+		return Task{}, fmt.Errorf("reopen: taskID[%d]: %w", id, ErrReasonRequired)
+	}
+
+	task, err := s.storer.QueryByID(ctx, id)
+	if err != nil {
+		return Task{}, fmt.Errorf("query: taskID[%d]: %w", id, err)
+	}
+
+	// Reopening is not part of the workflow table so a plain transition can
+	// never skip the reason.
+	if task.Status != StatusDone {
+		return Task{}, fmt.Errorf("taskID[%d] from[%s] to[%s]: %w", id, task.Status, StatusTodo, ErrInvalidTransition)
+	}
+
+	// A done parent needs every subtask done, so it has to be reopened
+	// before any of them.
+	if task.ParentID.Valid {
+		parentID := int(task.ParentID.Int32)
+		parent, err := s.storer.QueryByID(ctx, parentID)
+		if err != nil {
+			return Task{}, fmt.Errorf("query: parent taskID[%d]: %w", parentID, err)
+		}
+		if parent.Status == StatusDone {
+			return Task{}, fmt.Errorf("taskID[%d] parent taskID[%d] is done: %w", id, parentID, ErrInvalidParent)
+		}
+	}
+
+	// Likewise a done task needs its blockers closed.
+	dependents, err := s.storer.QueryDependents(ctx, id)
+	if err != nil {
+		return Task{}, fmt.Errorf("query dependents: taskID[%d]: %w", id, err)
+	}
+	for _, dependent := range dependents {
+		if dependent.Status == StatusDone {
+			return Task{}, fmt.Errorf("taskID[%d] blocks done taskID[%d]: %w", id, dependent.ID, ErrBlocksDoneTasks)
+		}
+	}
+
+	task.FinishedAt = sql.NullTime{}
+	task.LateBy = 0
+	task.ReopenCount++
+
+	task, err = s.transition(ctx, task, StatusTodo, changedBy, reason)
+	if err != nil {
+		return Task{}, err
+	}
+
+	tasksReopened.Inc()
+
+	return task, nil
+}
+
+// transition stores the task in the new status along with the record of the
+// change.
+func (s *Business) transition(ctx context.Context, task Task, to Status, changedBy sql.NullInt32, reason string) (Task, error) {
+	task, tr := changeStatus(task, to, changedBy, reason, nowUTC())
+
+	if err := s.storer.Transition(ctx, task, tr); err != nil {
+		return Task{}, fmt.Errorf("transition: taskID[%d]: %w", task.ID, err)
+	}
+
+	return task, nil
+}
+
+// changeStatus returns the task in the new status along with the record of
+// the change. Moving to done sets the finish time and, past the due date, how
+// late the task was.
+func changeStatus(task Task, to Status, changedBy sql.NullInt32, reason string, now time.Time) (Task, Transition) {
+	tr := Transition{
+		TaskID:    task.ID,
+		From:      task.Status,
+		To:        to,
+		ChangedBy: changedBy,
+		ChangedAt: now,
+		Reason:    reason,
+	}
+
+	task.Status = to
+	if to == StatusDone {
+		task.FinishedAt = sql.NullTime{Time: now, Valid: true}
+		if task.DueAt.Valid && now.After(task.DueAt.Time) {
+			task.LateBy = now.Sub(task.DueAt.Time)
+		}
+	}
+
+	return task, tr
+}
+
+// QueryTransitions retrieves the status changes of a task, oldest first.
+func (s *Business) QueryTransitions(ctx context.Context, id int) ([]Transition, error) {
+	if _, err := s.storer.QueryByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("query: taskID[%d]: %w", id, err)
+	}
+
+	trs, err := s.storer.QueryTransitions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("query transitions: taskID[%d]: %w", id, err)
+	}
+
+	return trs, nil
+}
+
+// QueryChildren retrieves the direct subtasks of a task in id order.
+func (s *Business) QueryChildren(ctx context.Context, id int) ([]Task, error) {
+	tasks, err := s.storer.QueryChildren(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("query children: taskID[%d]: %w", id, err)
+	}
+
+	return tasks, nil
+}
+
+// QueryProgress retrieves the progress of the direct subtasks of each task.
+// Tasks without subtasks are missing from the map.
+func (s *Business) QueryProgress(ctx context.Context, ids []int) (map[int]Progress, error) {
+	if len(ids) == 0 {
+		return map[int]Progress{}, nil
+	}
+
+	progress, err := s.storer.QueryProgress(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query progress: %w", err)
+	}
+
+	return progress, nil
 }
 
-// Finish updates the finishedAt timestamp for a task.
-func (s *Business) Finish(ctx context.Context, id int) error {
-	now := time.Now()
+// openSubtasks returns every subtask below the task that is neither done
+// nor cancelled, each parent ahead of its own subtasks. The subtasks of
+// closed subtasks are still visited, as a cancelled subtask may have open
+// ones below it.
+func (s *Business) openSubtasks(ctx context.Context, id int) ([]Task, error) {
+	var open []Task
 
-	finishedAt := sql.NullTime{
-		Time:  time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.Local),
-		Valid: true,
+	queue := []int{id}
+	for len(queue) > 0 {
+		children, err := s.storer.QueryChildren(ctx, queue[0])
+		if err != nil {
+			return nil, fmt.Errorf("query children: taskID[%d]: %w", queue[0], err)
+		}
+		queue = queue[1:]
+
+		for _, child := range children {
+			if child.Status != StatusDone && child.Status != StatusCancelled {
+				open = append(open, child)
+			}
+			queue = append(queue, child.ID)
+		}
 	}
 
-	query := "UPDATE task SET finished_at = ? WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, finishedAt, id)
+	return open, nil
+}
+
+// checkParent verifies the parent, when there is one, is an open task of the
+// same project and is neither the task itself nor one of its subtasks. A
+// new task passes a zero id.
+func (s *Business) checkParent(ctx context.Context, id int, projectID int, parentID sql.NullInt32) error {
+	if !parentID.Valid {
+		return nil
+	}
+
+	parent, err := s.storer.QueryByID(ctx, int(parentID.Int32))
 	if err != nil {
-		return fmt.Errorf("failed to finish task with ID %d: %v", id, err)
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("parent taskID[%d] does not exist: %w", parentID.Int32, ErrInvalidParent)
+		}
+		return fmt.Errorf("query: parent taskID[%d]: %w", parentID.Int32, err)
+	}
+
+	if parent.ProjectID != projectID {
+		return fmt.Errorf("parent taskID[%d] belongs to projectID[%d]: %w", parent.ID, parent.ProjectID, ErrInvalidParent)
+	}
+	if parent.Status == StatusDone || parent.Status == StatusCancelled {
+		return fmt.Errorf("parent taskID[%d] is %s: %w", parent.ID, parent.Status, ErrInvalidParent)
+	}
+
+	// Walking up from the parent reaches the task when the parent is the
+	// task or sits below it.
+	for ancestor := parent; ; {
+		if ancestor.ID == id {
+			return fmt.Errorf("taskID[%d] parent taskID[%d]: %w", id, parent.ID, ErrParentCycle)
+		}
+		if !ancestor.ParentID.Valid {
+			return nil
+		}
+
+		next := int(ancestor.ParentID.Int32)
+		if ancestor, err = s.storer.QueryByID(ctx, next); err != nil {
+			return fmt.Errorf("query: ancestor taskID[%d]: %w", next, err)
+		}
+	}
+}
+
+// nowUTC returns the current time in UTC to the second, the way the stores
+// keep the creation and finish times, so the date filters compare them the
+// same way as due dates.
+func nowUTC() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// dueAt keeps due dates in UTC to the second, so both stores compare them
+// with the query bounds the same way whatever zone the caller used.
+func dueAt(t sql.NullTime) sql.NullTime {
+	if !t.Valid {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.Time.UTC().Truncate(time.Second), Valid: true}
+}
+
+// checkAssignee verifies the assigned user, when there is one, is active and
+// a member of the project.
+func (s *Business) checkAssignee(ctx context.Context, projectID int, assignedTo sql.NullInt32) error {
+	if !assignedTo.Valid {
+		return nil
+	}
+	userID := int(assignedTo.Int32)
+
+	user, err := s.userBus.QueryById(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve assigned user with ID %d: %w", userID, err)
+	}
+	if !user.Active {
+		return fmt.Errorf("assigned userID[%d]: %w", userID, userbus.ErrUserInactive)
+	}
+
+	if _, err := s.projectBus.QueryMember(ctx, projectID, userID); err != nil {
+		if errors.Is(err, projectbus.ErrMemberNotFound) {
+			return fmt.Errorf("assigned userID[%d] projectID[%d]: %w", userID, projectID, ErrAssigneeNotMember)
+		}
+		return err
 	}
 
 	return nil
@@ -7,67 +7,52 @@ import (
 	"time"
 
 	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/projectbus/stores/projectmem"
 	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/taskbus/stores/taskmem"
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/usermem"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
-var (
-	db       *sql.DB
-	mock     sqlmock.Sqlmock
-	business *taskbus.Business
-)
-
-func setupMockDB(t *testing.T) {
-	var err error
-	db, mock, err = sqlmock.New()
-	assert.NoError(t, err)
+// setup seeds three active users, one inactive user, an active project and
+// an inactive project. The first two users are members of the active project
+// and the outsider is not.
+func setup(t *testing.T) *taskbus.Business {
+	ctx := context.Background()
+	db := memdb.New()
 
-	userBus := userbus.NewBusiness(db)
-	projectBus := projectbus.NewBusiness(db, userBus)
-	business = taskbus.NewBusiness(db, userBus, projectBus)
-}
+	userBus := userbus.NewBusiness(usermem.NewStore(db))
+	for _, email := range []string{"creator@example.com", "assigned@example.com", "inactive@example.com", "outsider@example.com"} {
+		_, err := userBus.Create(ctx, userbus.NewUser{Name: email, Email: email})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, userBus.Deactivate(ctx, 3))
 
-func mockTaskRows() *sqlmock.Rows {
-	return sqlmock.NewRows([]string{"id", "title", "description", "created_at", "finished_at", "created_by", "assigned_to", "project_id"}).
-		AddRow(1, "Task 1", "Description 1", time.Now(), sql.NullTime{Valid: false}, 1, sql.NullInt32{}, 3).
-		AddRow(2, "Task 2", "Description 2", time.Now(), sql.NullTime{Valid: false}, 1, sql.NullInt32{}, 3)
-}
+	projectBus := projectbus.NewBusiness(projectmem.NewStore(db), userBus)
+	for _, name := range []string{"Active Project", "Inactive Project"} {
+		_, err := projectBus.Create(ctx, projectbus.NewProject{Name: name, CreatedBy: 1})
+		assert.NoError(t, err)
+	}
+	_, err := projectBus.AddMember(ctx, 1, projectbus.NewMember{UserID: 2, Role: projectbus.MemberRoleMember})
+	assert.NoError(t, err)
+	assert.NoError(t, projectBus.Deactivate(ctx, 2))
 
-func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
-	assert.NoError(t, mock.ExpectationsWereMet())
+	return taskbus.NewBusiness(taskmem.NewStore(db), userBus, projectBus)
 }
 
 func TestCreate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
-
-	mock.ExpectQuery("SELECT id, name, active, created_at, created_by FROM project WHERE id = ?").
-		WithArgs(3).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "active", "created_at", "created_by"}).
-			AddRow(3, "Project Name", true, time.Now(), 1))
-
-	mock.ExpectQuery("SELECT id, name, email, active, created_at, updated_at FROM users WHERE id = ?").
-		WithArgs(int64(1)).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "active", "created_at", "updated_at"}).
-			AddRow(1, "Creator Name", "creator@example.com", true, time.Now(), time.Now()))
-
-	mock.ExpectQuery("SELECT id, name, email, active, created_at, updated_at FROM users WHERE id = ?").
-		WithArgs(int64(2)).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "active", "created_at", "updated_at"}).
-			AddRow(2, "Assigned Name", "assigned@example.com", true, time.Now(), time.Now()))
-
-	mock.ExpectExec("INSERT INTO task \\(title, description, created_by, assigned_to, project_id, created_at, finished_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
-		WithArgs("New Task", "This is a new task", 1, sql.NullInt32{Int32: 2, Valid: true}, 3, sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	business := setup(t)
 
 	ctx := context.Background()
 	newTask := taskbus.NewTask{
 		Title:       "New Task",
 		Description: "This is a new task",
-		ProjectID:   3,
+		ProjectID:   1,
 		CreatedBy:   1,
 		AssignedTo:  sql.NullInt32{Int32: 2, Valid: true},
 	}
@@ -77,104 +62,614 @@ func TestCreate(t *testing.T) {
 	assert.Equal(t, 1, task.ID)
 	assert.Equal(t, "New Task", task.Title)
 	assert.Equal(t, "This is a new task", task.Description)
-	assert.Equal(t, 3, task.ProjectID)
+	assert.Equal(t, 1, task.ProjectID)
 	assert.NotEmpty(t, task.CreatedAt)
 	assert.True(t, task.CreatedAt.After(time.Now().Add(-time.Hour)))
 	assert.False(t, task.FinishedAt.Valid)
+	assert.Equal(t, taskbus.PriorityMedium, task.Priority, "medium is the default priority")
+	assert.False(t, task.DueAt.Valid)
+}
 
-	assertMockExpectations(t, mock)
+func TestCreateDueAt(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	dueAt := time.Date(2026, 11, 2, 18, 0, 0, 500, saoPaulo)
+
+	task, err := business.Create(ctx, taskbus.NewTask{
+		Title:     "Task",
+		ProjectID: 1,
+		CreatedBy: 1,
+		Priority:  taskbus.PriorityUrgent,
+		DueAt:     sql.NullTime{Time: dueAt, Valid: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, taskbus.PriorityUrgent, task.Priority)
+	assert.Equal(t, time.Date(2026, 11, 2, 21, 0, 0, 0, time.UTC), task.DueAt.Time, "due dates are kept in UTC to the second")
 }
 
-func TestQuery(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestCreateRules(t *testing.T) {
+	business := setup(t)
+
+	tests := map[string]struct {
+		nt   taskbus.NewTask
+		want error
+	}{
+		"missing project":   {taskbus.NewTask{Title: "Task", ProjectID: 9, CreatedBy: 1}, projectbus.ErrNotFound},
+		"inactive project":  {taskbus.NewTask{Title: "Task", ProjectID: 2, CreatedBy: 1}, projectbus.ErrProjectInactive},
+		"missing creator":   {taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 9}, userbus.ErrNotFound},
+		"inactive creator":  {taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 3}, userbus.ErrUserInactive},
+		"inactive assignee": {taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1, AssignedTo: sql.NullInt32{Int32: 3, Valid: true}}, userbus.ErrUserInactive},
+		"outside assignee":  {taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1, AssignedTo: sql.NullInt32{Int32: 4, Valid: true}}, taskbus.ErrAssigneeNotMember},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := business.Create(context.Background(), tt.nt)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
 
-	mock.ExpectQuery("SELECT id, title, description, created_at, finished_at, created_by, assigned_to, project_id FROM task").
-		WillReturnRows(mockTaskRows())
+func TestUpdate(t *testing.T) {
+	business := setup(t)
 
 	ctx := context.Background()
-	tasks, err := business.Query(ctx)
+	task, err := business.Create(ctx, taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
 
+	updateTask := taskbus.UpdateTask{Title: "Update Title", Description: "Update Description"}
+	err = business.Update(ctx, task.ID, updateTask)
 	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, "Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.NotEmpty(t, tasks[0].CreatedAt)
-	assert.True(t, tasks[0].CreatedAt.After(time.Now().Add(-time.Hour)))
-	assert.WithinDuration(t, time.Now(), tasks[0].CreatedAt, time.Minute)
-	assert.False(t, tasks[0].FinishedAt.Valid)
-	assertMockExpectations(t, mock)
+
+	task, err = business.QueryByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Update Title", task.Title)
+	assert.Equal(t, "Update Description", task.Description)
+	assert.Equal(t, 1, task.ProjectID)
+
+	updateTask.AssignedTo = sql.NullInt32{Int32: 4, Valid: true}
+	err = business.Update(ctx, task.ID, updateTask)
+	assert.ErrorIs(t, err, taskbus.ErrAssigneeNotMember)
+
+	updateTask.AssignedTo = sql.NullInt32{Int32: 2, Valid: true}
+	assert.NoError(t, business.Update(ctx, task.ID, updateTask))
+
+	updateTask.Priority = taskbus.PriorityHigh
+	updateTask.DueAt = sql.NullTime{Time: time.Now().AddDate(0, 0, 1), Valid: true}
+	assert.NoError(t, business.Update(ctx, task.ID, updateTask))
+
+	updateTask.Priority = taskbus.Priority{}
+	updateTask.DueAt = sql.NullTime{}
+	assert.NoError(t, business.Update(ctx, task.ID, updateTask))
+
+	task, err = business.QueryByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, taskbus.PriorityHigh, task.Priority, "an empty priority keeps the current one")
+	assert.False(t, task.DueAt.Valid)
 }
 
-func TestQueryByID(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestQuery(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	for _, title := range []string{"Buy milk", "Walk dog", "Buy bread", "Pay rent"} {
+		_, err := business.Create(ctx, taskbus.NewTask{Title: title, ProjectID: 1, CreatedBy: 1, AssignedTo: sql.NullInt32{Int32: 2, Valid: true}})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, business.Finish(ctx, 2, sql.NullInt32{}))
 
-	mock.ExpectQuery("SELECT id, title, description, project_id, created_at, finished_at, created_by, assigned_to FROM task WHERE id = ?").
-		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "project_id", "created_at", "finished_at", "created_by", "assigned_to"}).
-			AddRow(1, "Task 1", "Description 1", 3, time.Now(), sql.NullTime{}, 1, sql.NullInt32{}))
+	title := "buy"
+	tasks, err := business.Query(ctx, taskbus.QueryFilter{Title: &title}, []order.By{order.NewBy(taskbus.OrderByTitle, order.ASC)}, page.MustParse("1", "10"))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 2) {
+		assert.Equal(t, "Buy bread", tasks[0].Title)
+		assert.Equal(t, "Buy milk", tasks[1].Title)
+	}
+
+	finished := false
+	filter := taskbus.QueryFilter{Finished: &finished}
+	tasks, err = business.Query(ctx, filter, []order.By{order.NewBy(taskbus.OrderByID, order.DESC)}, page.MustParse("2", "2"))
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 1) {
+		assert.Equal(t, 1, tasks[0].ID)
+	}
+
+	count, err := business.Count(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestQueryAfter(t *testing.T) {
+	business := setup(t)
 
 	ctx := context.Background()
-	task, err := business.QueryByID(ctx, 1)
+	for _, title := range []string{"One", "Two", "Three", "Four", "Five"} {
+		_, err := business.Create(ctx, taskbus.NewTask{Title: title, ProjectID: 1, CreatedBy: 1})
+		assert.NoError(t, err)
+	}
+
+	var ids []int
+	var after *taskbus.Cursor
+	for {
+		tasks, err := business.QueryAfter(ctx, taskbus.QueryFilter{}, after, 2)
+		assert.NoError(t, err)
+		if len(tasks) == 0 {
+			break
+		}
+
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		cursor := taskbus.CursorOf(tasks[len(tasks)-1])
+		after = &cursor
+	}
 
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
+func TestFinish(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	task, err := business.Create(ctx, taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+
+	err = business.Finish(ctx, task.ID, sql.NullInt32{Int32: 1, Valid: true})
+	assert.NoError(t, err)
+
+	task, err = business.QueryByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.True(t, task.FinishedAt.Valid)
+	assert.WithinDuration(t, time.Now(), task.FinishedAt.Time, time.Minute)
+	assert.Equal(t, taskbus.StatusDone, task.Status)
+
+	err = business.Finish(ctx, task.ID, sql.NullInt32{Int32: 1, Valid: true})
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition, "done is final")
+}
+
+func TestReopen(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	task, err := business.Create(ctx, taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+
+	by := sql.NullInt32{Int32: 2, Valid: true}
+
+	_, err = business.Reopen(ctx, task.ID, "not done", by)
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition, "only finished tasks reopen")
+
+	for i := 1; i <= 2; i++ {
+		assert.NoError(t, business.Finish(ctx, task.ID, by))
+
+		_, err = business.Reopen(ctx, task.ID, " ", by)
+		assert.ErrorIs(t, err, taskbus.ErrReasonRequired)
+
+		task, err = business.Reopen(ctx, task.ID, "missed a step", by)
+		assert.NoError(t, err)
+		assert.Equal(t, taskbus.StatusTodo, task.Status)
+		assert.False(t, task.FinishedAt.Valid)
+		assert.Equal(t, i, task.ReopenCount)
+	}
+
+	task, err = business.QueryByID(ctx, task.ID)
 	assert.NoError(t, err)
-	assert.Equal(t, "Task 1", task.Title)
-	assert.Equal(t, "Description 1", task.Description)
-	assert.Equal(t, 3, task.ProjectID)
-	assert.NotEmpty(t, task.CreatedAt)
-	assert.True(t, task.CreatedAt.After(time.Now().Add(-time.Hour)))
 	assert.False(t, task.FinishedAt.Valid)
-	assertMockExpectations(t, mock)
+	assert.Equal(t, 2, task.ReopenCount)
+
+	trs, err := business.QueryTransitions(ctx, task.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, trs, 4) {
+		assert.Equal(t, taskbus.StatusDone, trs[3].From)
+		assert.Equal(t, taskbus.StatusTodo, trs[3].To)
+		assert.Equal(t, by, trs[3].ChangedBy)
+		assert.Equal(t, "missed a step", trs[3].Reason)
+		assert.Empty(t, trs[2].Reason)
+	}
 }
 
-func TestUpdate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestFinishLate(t *testing.T) {
+	business := setup(t)
 
-	mock.ExpectExec("^UPDATE task SET title = \\?, description = \\?, assigned_to = \\? WHERE id = \\?$").
-		WithArgs("Update Title", "Update Description", sql.NullInt32{}, 1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	ctx := context.Background()
+	late, err := business.Create(ctx, taskbus.NewTask{Title: "Late", ProjectID: 1, CreatedBy: 1, DueAt: sql.NullTime{Time: time.Now().Add(-2 * time.Hour), Valid: true}})
+	assert.NoError(t, err)
+	onTime, err := business.Create(ctx, taskbus.NewTask{Title: "On time", ProjectID: 1, CreatedBy: 1, DueAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, business.Finish(ctx, late.ID, sql.NullInt32{}))
+	assert.NoError(t, business.Finish(ctx, onTime.ID, sql.NullInt32{}))
+
+	late, err = business.QueryByID(ctx, late.ID)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*time.Hour, late.LateBy, float64(2*time.Second))
+
+	onTime, err = business.QueryByID(ctx, onTime.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, onTime.LateBy)
+
+	late, err = business.Reopen(ctx, late.ID, "wrong fix", sql.NullInt32{})
+	assert.NoError(t, err)
+	assert.Zero(t, late.LateBy, "reopening clears the lateness")
+}
+
+func TestQueryDue(t *testing.T) {
+	business := setup(t)
 
 	ctx := context.Background()
-	updateTask := taskbus.UpdateTask{Title: "Update Title", Description: "Update Description"}
-	err := business.Update(ctx, 1, updateTask)
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+
+	// Sunday evening in São Paulo is already Monday in UTC.
+	now := time.Date(2026, 10, 18, 22, 0, 0, 0, saoPaulo)
+
+	dues := []struct {
+		title string
+		dueAt time.Time
+	}{
+		{"Yesterday", now.AddDate(0, 0, -1)},
+		{"Earlier today", now.Add(-time.Hour)},
+		{"Later today", now.Add(time.Hour)},
+		{"Monday", now.AddDate(0, 0, 1)},
+		{"Last Monday", now.AddDate(0, 0, -6)},
+		{"Done yesterday", now.AddDate(0, 0, -1)},
+	}
+	for _, d := range dues {
+		_, err := business.Create(ctx, taskbus.NewTask{Title: d.title, ProjectID: 1, CreatedBy: 1, DueAt: sql.NullTime{Time: d.dueAt, Valid: true}})
+		assert.NoError(t, err)
+	}
+	_, err := business.Create(ctx, taskbus.NewTask{Title: "No due date", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, business.Finish(ctx, 6, sql.NullInt32{}))
+
+	titles := func(filter taskbus.QueryFilter) []string {
+		tasks, err := business.Query(ctx, filter, []order.By{order.NewBy(taskbus.OrderByDueAt, order.ASC)}, page.MustParse("1", "10"))
+		assert.NoError(t, err)
+
+		var titles []string
+		for _, task := range tasks {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+
+	assert.Equal(t, []string{"Last Monday", "Yesterday", "Earlier today"}, titles(taskbus.QueryFilter{}.Overdue(now)))
+	assert.Equal(t, []string{"Earlier today", "Later today"}, titles(taskbus.QueryFilter{}.DueToday(now)))
+	assert.Equal(t, []string{"Last Monday", "Yesterday", "Earlier today", "Later today"}, titles(taskbus.QueryFilter{}.DueThisWeek(now)))
+	assert.Equal(t, []string{"Monday"}, titles(taskbus.QueryFilter{}.DueThisWeek(now.AddDate(0, 0, 1))))
+}
+
+func TestSubtasks(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	create := func(title string, parentID int) taskbus.Task {
+		nt := taskbus.NewTask{Title: title, ProjectID: 1, CreatedBy: 1}
+		if parentID != 0 {
+			nt.ParentID = sql.NullInt32{Int32: int32(parentID), Valid: true}
+		}
+		task, err := business.Create(ctx, nt)
+		assert.NoError(t, err)
+		return task
+	}
+
+	parent := create("Move house", 0)
+	boxes := create("Pack boxes", parent.ID)
+	books := create("Pack books", boxes.ID)
+	truck := create("Rent a truck", parent.ID)
+
+	_, err := business.Create(ctx, taskbus.NewTask{Title: "Orphan", ProjectID: 1, CreatedBy: 1, ParentID: sql.NullInt32{Int32: 99, Valid: true}})
+	assert.ErrorIs(t, err, taskbus.ErrInvalidParent)
+
+	cycle := taskbus.UpdateTask{Title: parent.Title, ParentID: sql.NullInt32{Int32: int32(books.ID), Valid: true}}
+	assert.ErrorIs(t, business.Update(ctx, parent.ID, cycle), taskbus.ErrParentCycle)
+
+	cycle.ParentID = sql.NullInt32{Int32: int32(parent.ID), Valid: true}
+	assert.ErrorIs(t, business.Update(ctx, parent.ID, cycle), taskbus.ErrParentCycle, "a task can not be its own parent")
+
+	children, err := business.QueryChildren(ctx, parent.ID)
+	assert.NoError(t, err)
+	assert.Len(t, children, 2)
+
+	assert.ErrorIs(t, business.Finish(ctx, parent.ID, sql.NullInt32{}), taskbus.ErrOpenSubtasks)
+	assert.ErrorIs(t, business.Delete(ctx, parent.ID), taskbus.ErrHasSubtasks)
 
+	_, err = business.Transition(ctx, truck.ID, taskbus.StatusCancelled, sql.NullInt32{})
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
+	assert.NoError(t, business.Finish(ctx, books.ID, sql.NullInt32{}))
+
+	progress, err := business.QueryProgress(ctx, []int{parent.ID, boxes.ID, books.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]taskbus.Progress{
+		parent.ID: {Done: 0, Total: 1},
+		boxes.ID:  {Done: 1, Total: 1},
+	}, progress, "cancelled subtasks are not counted")
+
+	_, err = business.Create(ctx, taskbus.NewTask{Title: "Late", ProjectID: 1, CreatedBy: 1, ParentID: sql.NullInt32{Int32: int32(books.ID), Valid: true}})
+	assert.ErrorIs(t, err, taskbus.ErrInvalidParent, "a done task takes no subtasks")
+
+	_, err = business.Transition(ctx, boxes.ID, taskbus.StatusBlocked, sql.NullInt32{})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, business.FinishWithSubtasks(ctx, parent.ID, sql.NullInt32{}), taskbus.ErrInvalidTransition, "blocked subtasks can not be finished")
+
+	parent, err = business.QueryByID(ctx, parent.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, taskbus.StatusTodo, parent.Status)
+
+	_, err = business.Transition(ctx, boxes.ID, taskbus.StatusInProgress, sql.NullInt32{})
+	assert.NoError(t, err)
+	assert.NoError(t, business.FinishWithSubtasks(ctx, parent.ID, sql.NullInt32{}))
+
+	for _, id := range []int{parent.ID, boxes.ID, books.ID} {
+		task, err := business.QueryByID(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, taskbus.StatusDone, task.Status)
+	}
 }
 
-func TestFinish(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestDependencies(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	create := func(title string) taskbus.Task {
+		task, err := business.Create(ctx, taskbus.NewTask{Title: title, ProjectID: 1, CreatedBy: 1})
+		assert.NoError(t, err)
+		return task
+	}
+
+	design := create("Design")
+	build := create("Build")
+	ship := create("Ship")
+
+	_, err := business.AddDependency(ctx, build.ID, design.ID)
+	assert.NoError(t, err)
+	dep, err := business.AddDependency(ctx, ship.ID, build.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, build.ID, dep.BlockerID)
+
+	_, err = business.AddDependency(ctx, ship.ID, build.ID)
+	assert.ErrorIs(t, err, taskbus.ErrDependencyExists)
+
+	_, err = business.AddDependency(ctx, design.ID, ship.ID)
+	assert.ErrorIs(t, err, taskbus.ErrDependencyCycle)
 
-	now := time.Now()
-	finishedAt := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.Local)
-	nullFinishedAt := sql.NullTime{Time: finishedAt, Valid: true}
+	_, err = business.AddDependency(ctx, design.ID, design.ID)
+	assert.ErrorIs(t, err, taskbus.ErrDependencyCycle, "a task can not block itself")
+
+	_, err = business.AddDependency(ctx, design.ID, 99)
+	assert.ErrorIs(t, err, taskbus.ErrInvalidBlocker)
+
+	blockers, err := business.QueryBlockers(ctx, ship.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, blockers, 1) {
+		assert.Equal(t, build.ID, blockers[0].ID)
+	}
+
+	assert.ErrorIs(t, business.Finish(ctx, build.ID, sql.NullInt32{}), taskbus.ErrOpenBlockers)
+
+	_, err = business.Transition(ctx, design.ID, taskbus.StatusCancelled, sql.NullInt32{})
+	assert.NoError(t, err)
+	assert.NoError(t, business.Finish(ctx, build.ID, sql.NullInt32{}), "cancelled blockers hold nothing up")
 
-	mock.ExpectExec("^UPDATE task SET finished_at = \\? WHERE id = \\?$").
-		WithArgs(nullFinishedAt, 1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, business.Finish(ctx, ship.ID, sql.NullInt32{}))
+	_, err = business.Reopen(ctx, build.ID, "failed the checks", sql.NullInt32{})
+	assert.ErrorIs(t, err, taskbus.ErrBlocksDoneTasks, "the task it blocks is done")
+
+	review := create("Review")
+	_, err = business.AddDependency(ctx, ship.ID, review.ID)
+	assert.ErrorIs(t, err, taskbus.ErrOpenBlockers, "a done task can not get an open blocker")
+	_, err = business.AddDependency(ctx, ship.ID, design.ID)
+	assert.NoError(t, err, "a cancelled blocker holds nothing up")
+	assert.NoError(t, business.RemoveDependency(ctx, ship.ID, design.ID))
+
+	_, err = business.Reopen(ctx, ship.ID, "failed the checks", sql.NullInt32{})
+	assert.NoError(t, err)
+	_, err = business.Reopen(ctx, build.ID, "failed the checks", sql.NullInt32{})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, business.RemoveDependency(ctx, ship.ID, design.ID), taskbus.ErrDependencyNotFound)
+	assert.NoError(t, business.RemoveDependency(ctx, ship.ID, build.ID))
+
+	assert.NoError(t, business.Delete(ctx, design.ID), "the links go with the task")
+	blockers, err = business.QueryBlockers(ctx, build.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, blockers)
+}
+
+func TestFinishWithSubtasksBlocked(t *testing.T) {
+	business := setup(t)
 
 	ctx := context.Background()
-	err := business.Finish(ctx, 1)
+	parent, err := business.Create(ctx, taskbus.NewTask{Title: "Release", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+
+	parentID := sql.NullInt32{Int32: int32(parent.ID), Valid: true}
+	notes, err := business.Create(ctx, taskbus.NewTask{Title: "Write notes", ProjectID: 1, CreatedBy: 1, ParentID: parentID})
+	assert.NoError(t, err)
+	tag, err := business.Create(ctx, taskbus.NewTask{Title: "Tag", ProjectID: 1, CreatedBy: 1, ParentID: parentID})
+	assert.NoError(t, err)
+	review, err := business.Create(ctx, taskbus.NewTask{Title: "Security review", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
 
-	assert.True(t, finishedAt.After(time.Now().Add(-time.Hour)))
+	_, err = business.AddDependency(ctx, tag.ID, notes.ID)
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
+	_, err = business.AddDependency(ctx, tag.ID, review.ID)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, business.FinishWithSubtasks(ctx, parent.ID, sql.NullInt32{}), taskbus.ErrOpenBlockers)
+
+	notes, err = business.QueryByID(ctx, notes.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, taskbus.StatusTodo, notes.Status, "nothing is finished when a blocker is left out")
+
+	assert.NoError(t, business.RemoveDependency(ctx, tag.ID, review.ID))
+	assert.NoError(t, business.FinishWithSubtasks(ctx, parent.ID, sql.NullInt32{}), "blockers finished along are fine")
 }
 
-func TestDelete(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestCriticalPath(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	ids := make(map[string]int)
+	for _, title := range []string{"Foundation", "Walls", "Roof", "Windows", "Paint", "Garden", "Done"} {
+		task, err := business.Create(ctx, taskbus.NewTask{Title: title, ProjectID: 1, CreatedBy: 1})
+		assert.NoError(t, err)
+		ids[title] = task.ID
+	}
+
+	path, err := business.CriticalPath(ctx, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, path, "no links, no path")
+
+	links := [][2]string{
+		{"Walls", "Foundation"},
+		{"Roof", "Walls"},
+		{"Windows", "Walls"},
+		{"Paint", "Roof"},
+		{"Paint", "Windows"},
+		{"Garden", "Foundation"},
+		{"Foundation", "Done"},
+	}
+	for _, link := range links {
+		_, err := business.AddDependency(ctx, ids[link[0]], ids[link[1]])
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, business.Finish(ctx, ids["Done"], sql.NullInt32{}))
 
-	mock.ExpectExec("DELETE FROM task WHERE id = ?").
-		WithArgs(1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	titles := func() []string {
+		path, err := business.CriticalPath(ctx, 1)
+		assert.NoError(t, err)
+
+		var titles []string
+		for _, task := range path {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+
+	assert.Equal(t, []string{"Foundation", "Walls", "Roof", "Paint"}, titles(), "ties go to the lowest id and finished tasks are left out")
+
+	_, err = business.Transition(ctx, ids["Roof"], taskbus.StatusCancelled, sql.NullInt32{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Foundation", "Walls", "Windows", "Paint"}, titles())
+
+	_, err = business.CriticalPath(ctx, 99)
+	assert.ErrorIs(t, err, projectbus.ErrNotFound)
+}
+
+func TestFinishOpenGrandchild(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	create := func(title string, parentID int) taskbus.Task {
+		nt := taskbus.NewTask{Title: title, ProjectID: 1, CreatedBy: 1}
+		if parentID != 0 {
+			nt.ParentID = sql.NullInt32{Int32: int32(parentID), Valid: true}
+		}
+		task, err := business.Create(ctx, nt)
+		assert.NoError(t, err)
+		return task
+	}
+
+	parent := create("Move house", 0)
+	child := create("Pack boxes", parent.ID)
+	grandchild := create("Pack books", child.ID)
+
+	_, err := business.Transition(ctx, child.ID, taskbus.StatusCancelled, sql.NullInt32{})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, business.Finish(ctx, parent.ID, sql.NullInt32{}), taskbus.ErrOpenSubtasks, "the grandchild is open")
+
+	assert.NoError(t, business.Finish(ctx, grandchild.ID, sql.NullInt32{}))
+	assert.NoError(t, business.Finish(ctx, parent.ID, sql.NullInt32{}))
+}
+
+func TestReopenSubtask(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	parent, err := business.Create(ctx, taskbus.NewTask{Title: "Move house", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+	child, err := business.Create(ctx, taskbus.NewTask{Title: "Pack boxes", ProjectID: 1, CreatedBy: 1, ParentID: sql.NullInt32{Int32: int32(parent.ID), Valid: true}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, business.Finish(ctx, child.ID, sql.NullInt32{}))
+	assert.NoError(t, business.Finish(ctx, parent.ID, sql.NullInt32{}))
+
+	_, err = business.Reopen(ctx, child.ID, "one box left", sql.NullInt32{})
+	assert.ErrorIs(t, err, taskbus.ErrInvalidParent, "the parent is done")
+
+	child, err = business.QueryByID(ctx, child.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, taskbus.StatusDone, child.Status)
+
+	_, err = business.Reopen(ctx, parent.ID, "one box left", sql.NullInt32{})
+	assert.NoError(t, err)
+	_, err = business.Reopen(ctx, child.ID, "one box left", sql.NullInt32{})
+	assert.NoError(t, err)
+}
+
+func TestTransition(t *testing.T) {
+	business := setup(t)
 
 	ctx := context.Background()
-	err := business.Delete(ctx, 1)
+	task, err := business.Create(ctx, taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, taskbus.StatusTodo, task.Status)
+
+	by := sql.NullInt32{Int32: 2, Valid: true}
+	for _, to := range []taskbus.Status{taskbus.StatusInProgress, taskbus.StatusBlocked, taskbus.StatusInProgress, taskbus.StatusInReview} {
+		task, err = business.Transition(ctx, task.ID, to, by)
+		assert.NoError(t, err)
+		assert.Equal(t, to, task.Status)
+	}
+	assert.False(t, task.FinishedAt.Valid)
+
+	_, err = business.Transition(ctx, task.ID, taskbus.StatusTodo, by)
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition)
 
+	task, err = business.Transition(ctx, task.ID, taskbus.StatusCancelled, by)
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
+	assert.False(t, task.FinishedAt.Valid, "cancelled tasks are not finished")
+
+	_, err = business.Transition(ctx, task.ID, taskbus.StatusInProgress, by)
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition)
+
+	trs, err := business.QueryTransitions(ctx, task.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, trs, 5) {
+		assert.Equal(t, taskbus.StatusTodo, trs[0].From)
+		assert.Equal(t, taskbus.StatusInProgress, trs[0].To)
+		assert.Equal(t, taskbus.StatusCancelled, trs[4].To)
+		assert.Equal(t, by, trs[4].ChangedBy)
+		assert.WithinDuration(t, time.Now(), trs[4].ChangedAt, time.Minute)
+	}
+
+	status := taskbus.StatusCancelled
+	tasks, err := business.Query(ctx, taskbus.QueryFilter{Status: &status}, []order.By{taskbus.DefaultOrderBy}, page.MustParse("1", "10"))
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	assert.NoError(t, business.Delete(ctx, task.ID), "the history goes with the task")
+}
+
+func TestDelete(t *testing.T) {
+	business := setup(t)
+
+	ctx := context.Background()
+	task, err := business.Create(ctx, taskbus.NewTask{Title: "Task", ProjectID: 1, CreatedBy: 1})
+	assert.NoError(t, err)
+
+	err = business.Delete(ctx, task.ID)
+	assert.NoError(t, err)
+
+	_, err = business.QueryByID(ctx, task.ID)
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
+
+	err = business.Delete(ctx, task.ID)
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
+
+	err = business.Finish(ctx, task.ID, sql.NullInt32{})
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
 }
@@ -0,0 +1,17 @@
+package tokenbus
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RefreshToken represents a refresh token handed to a user. Only the SHA-256
+// hash of the token is stored, so the table can not be replayed if it leaks.
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+}
@@ -0,0 +1,84 @@
+// Package tokendb contains refresh token related CRUD functionality for MySQL.
+package tokendb
+
+import (
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Store manages the set of APIs for refresh token database access.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new refresh token into the database and returns its ID.
+func (s *Store) Create(ctx context.Context, rt tokenbus.RefreshToken) (int, error) {
+	query := "INSERT INTO refresh_token (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)"
+	result, err := sqldb.ExecContext(ctx, s.db, query, rt.UserID, rt.TokenHash, rt.CreatedAt, rt.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastInsertID), nil
+}
+
+// QueryByHash retrieves the refresh token with the specified hash.
+func (s *Store) QueryByHash(ctx context.Context, tokenHash string) (tokenbus.RefreshToken, error) {
+	query := "SELECT id, user_id, token_hash, created_at, expires_at, revoked_at FROM refresh_token WHERE token_hash = ?"
+	row := sqldb.QueryRowContext(ctx, s.db, query, tokenHash)
+
+	var rt tokenbus.RefreshToken
+	if err := row.Scan(&rt.ID, &rt.UserID, &rt.TokenHash, &rt.CreatedAt, &rt.ExpiresAt, &rt.RevokedAt); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return tokenbus.RefreshToken{}, fmt.Errorf("scan: %w", tokenbus.ErrNotFound)
+		}
+		return tokenbus.RefreshToken{}, err
+	}
+
+	return rt, nil
+}
+
+// Revoke marks a refresh token as revoked. It reports ErrNotFound when the
+// token does not exist or was already revoked.
+func (s *Store) Revoke(ctx context.Context, id int, at time.Time) error {
+	query := "UPDATE refresh_token SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"
+	result, err := sqldb.ExecContext(ctx, s.db, query, at, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("exec: %w", tokenbus.ErrNotFound)
+	}
+
+	return nil
+}
+
+// RevokeByUser marks every active refresh token of the user as revoked.
+func (s *Store) RevokeByUser(ctx context.Context, userID int, at time.Time) error {
+	query := "UPDATE refresh_token SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL"
+	if _, err := sqldb.ExecContext(ctx, s.db, query, at, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package tokendb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/tokenbus/stores/tokendb"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	db    *sql.DB
+	mock  sqlmock.Sqlmock
+	store *tokendb.Store
+)
+
+func setupMockDB(t *testing.T) {
+	var err error
+	db, mock, err = sqlmock.New()
+	assert.NoError(t, err)
+
+	store = tokendb.NewStore(db)
+}
+
+func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec("^INSERT INTO refresh_token \\(user_id, token_hash, created_at, expires_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)$").
+		WithArgs(1, "hash", now, now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	ctx := context.Background()
+	id, err := store.Create(ctx, tokenbus.RefreshToken{UserID: 1, TokenHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryByHash(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("^SELECT id, user_id, token_hash, created_at, expires_at, revoked_at FROM refresh_token WHERE token_hash = \\?$").
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "created_at", "expires_at", "revoked_at"}).
+			AddRow(4, 1, "hash", now, now.Add(time.Hour), nil))
+
+	mock.ExpectQuery("^SELECT id, user_id, token_hash, created_at, expires_at, revoked_at FROM refresh_token WHERE token_hash = \\?$").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	ctx := context.Background()
+	rt, err := store.QueryByHash(ctx, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, rt.ID)
+	assert.Equal(t, 1, rt.UserID)
+	assert.False(t, rt.RevokedAt.Valid)
+
+	_, err = store.QueryByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, tokenbus.ErrNotFound)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestRevoke(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec("^UPDATE refresh_token SET revoked_at = \\? WHERE id = \\? AND revoked_at IS NULL$").
+		WithArgs(now, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec("^UPDATE refresh_token SET revoked_at = \\? WHERE id = \\? AND revoked_at IS NULL$").
+		WithArgs(now, 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec("^UPDATE refresh_token SET revoked_at = \\? WHERE user_id = \\? AND revoked_at IS NULL$").
+		WithArgs(now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	ctx := context.Background()
+	assert.NoError(t, store.Revoke(ctx, 4, now))
+	assert.ErrorIs(t, store.Revoke(ctx, 4, now), tokenbus.ErrNotFound)
+	assert.NoError(t, store.RevokeByUser(ctx, 1, now))
+
+	assertMockExpectations(t, mock)
+}
@@ -0,0 +1,109 @@
+// Package tokenmem contains refresh token related CRUD functionality held in
+// memory.
+package tokenmem
+
+import (
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/sdk/memdb"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const table = "refresh_token"
+
+// Store manages the set of APIs for refresh token in-memory access.
+type Store struct {
+	db *memdb.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *memdb.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new refresh token and returns its ID. The user must exist
+// and the hash must be unique.
+func (s *Store) Create(ctx context.Context, rt tokenbus.RefreshToken) (int, error) {
+	var id int
+	err := s.db.Update(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			if toBusToken(r.ID, r.Value).TokenHash == rt.TokenHash {
+				return fmt.Errorf("token hash: %w", memdb.ErrDuplicate)
+			}
+		}
+
+		var err error
+		id, err = tx.Insert(table, rt, refs(rt)...)
+		return err
+	})
+
+	return id, err
+}
+
+// QueryByHash retrieves the refresh token with the specified hash.
+func (s *Store) QueryByHash(ctx context.Context, tokenHash string) (tokenbus.RefreshToken, error) {
+	var rt tokenbus.RefreshToken
+	err := s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			if t := toBusToken(r.ID, r.Value); t.TokenHash == tokenHash {
+				rt = t
+				return nil
+			}
+		}
+
+		return tokenbus.ErrNotFound
+	})
+
+	return rt, err
+}
+
+// Revoke marks a refresh token as revoked. It reports ErrNotFound when the
+// token does not exist or was already revoked.
+func (s *Store) Revoke(ctx context.Context, id int, at time.Time) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		v, exists := tx.Get(table, id)
+		if !exists {
+			return tokenbus.ErrNotFound
+		}
+
+		rt := toBusToken(id, v)
+		if rt.RevokedAt.Valid {
+			return tokenbus.ErrNotFound
+		}
+
+		rt.RevokedAt = sql.NullTime{Time: at, Valid: true}
+		_, err := tx.Replace(table, id, rt, refs(rt)...)
+		return err
+	})
+}
+
+// RevokeByUser marks every active refresh token of the user as revoked.
+func (s *Store) RevokeByUser(ctx context.Context, userID int, at time.Time) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			rt := toBusToken(r.ID, r.Value)
+			if rt.UserID != userID || rt.RevokedAt.Valid {
+				continue
+			}
+
+			rt.RevokedAt = sql.NullTime{Time: at, Valid: true}
+			if _, err := tx.Replace(table, rt.ID, rt, refs(rt)...); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func refs(rt tokenbus.RefreshToken) []memdb.Ref {
+	return []memdb.Ref{{Table: "users", ID: rt.UserID}}
+}
+
+func toBusToken(id int, v any) tokenbus.RefreshToken {
+	rt := v.(tokenbus.RefreshToken)
+	rt.ID = id
+	return rt
+}
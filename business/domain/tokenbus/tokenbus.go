@@ -0,0 +1,157 @@
+// Package tokenbus provides business access to the refresh tokens that let
+// users get new access tokens without sending their password again.
+package tokenbus
+
+import (
+	"TODO-list/business/domain/userbus"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Set of error variables for refresh token operations.
+var (
+	ErrNotFound = errors.New("refresh token not found")
+	ErrExpired  = errors.New("refresh token expired")
+	ErrRevoked  = errors.New("refresh token revoked")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, rt RefreshToken) (int, error)
+	QueryByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	Revoke(ctx context.Context, id int, at time.Time) error
+	RevokeByUser(ctx context.Context, userID int, at time.Time) error
+}
+
+// Business manages the set of APIs for refresh token access.
+type Business struct {
+	storer  Storer
+	userBus *userbus.Business
+	ttl     time.Duration
+}
+
+// NewBusiness constructs a refresh token business API. Issued tokens expire
+// after ttl.
+func NewBusiness(storer Storer, userBus *userbus.Business, ttl time.Duration) *Business {
+	return &Business{
+		storer:  storer,
+		userBus: userBus,
+		ttl:     ttl,
+	}
+}
+
+// Issue creates a refresh token for the user. The token itself is only
+// returned here; the store keeps its hash.
+func (b *Business) Issue(ctx context.Context, userID int) (string, RefreshToken, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+
+	now := time.Now()
+	rt := RefreshToken{
+		UserID:    userID,
+		TokenHash: hash(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(b.ttl),
+	}
+
+	id, err := b.storer.Create(ctx, rt)
+	if err != nil {
+		return "", RefreshToken{}, fmt.Errorf("create: %w", err)
+	}
+	rt.ID = id
+
+	return token, rt, nil
+}
+
+// Rotate exchanges a refresh token for a new one, revoking the old token. A
+// token presented after it was revoked means it was copied, so every token
+// of its user is revoked and the user has to log in again.
+func (b *Business) Rotate(ctx context.Context, token string) (string, RefreshToken, error) {
+	rt, err := b.storer.QueryByHash(ctx, hash(token))
+	if err != nil {
+		return "", RefreshToken{}, fmt.Errorf("query: %w", err)
+	}
+
+	now := time.Now()
+
+	if rt.RevokedAt.Valid {
+		if err := b.storer.RevokeByUser(ctx, rt.UserID, now); err != nil {
+			return "", RefreshToken{}, fmt.Errorf("revoke user: userID[%d]: %w", rt.UserID, err)
+		}
+		return "", RefreshToken{}, fmt.Errorf("tokenID[%d]: %w", rt.ID, ErrRevoked)
+	}
+
+	if now.After(rt.ExpiresAt) {
+		return "", RefreshToken{}, fmt.Errorf("tokenID[%d]: %w", rt.ID, ErrExpired)
+	}
+
+	usr, err := b.userBus.QueryById(ctx, rt.UserID)
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+	if !usr.Active {
+		return "", RefreshToken{}, fmt.Errorf("userID[%d]: %w", usr.ID, userbus.ErrUserInactive)
+	}
+
+	// The store only revokes a token still active, so of two requests racing
+	// with the same token just one gets a new token.
+	if err := b.storer.Revoke(ctx, rt.ID, now); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", RefreshToken{}, fmt.Errorf("tokenID[%d]: %w", rt.ID, ErrRevoked)
+		}
+		return "", RefreshToken{}, fmt.Errorf("revoke: tokenID[%d]: %w", rt.ID, err)
+	}
+
+	return b.Issue(ctx, rt.UserID)
+}
+
+// Revoke revokes a refresh token. Unknown and already revoked tokens are
+// ignored, so logging out twice is not an error.
+func (b *Business) Revoke(ctx context.Context, token string) error {
+	rt, err := b.storer.QueryByHash(ctx, hash(token))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if err := b.storer.Revoke(ctx, rt.ID, time.Now()); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("revoke: tokenID[%d]: %w", rt.ID, err)
+	}
+
+	return nil
+}
+
+// RevokeUser revokes every refresh token of the user.
+func (b *Business) RevokeUser(ctx context.Context, userID int) error {
+	if err := b.storer.RevokeByUser(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("revoke user: userID[%d]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// newToken returns 32 random bytes encoded for use in a URL or header.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
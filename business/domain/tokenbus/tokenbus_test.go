@@ -0,0 +1,107 @@
+package tokenbus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"TODO-list/business/domain/tokenbus"
+	"TODO-list/business/domain/tokenbus/stores/tokenmem"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/usermem"
+	"TODO-list/business/sdk/memdb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup seeds an active and an inactive user.
+func setup(t *testing.T, ttl time.Duration) (*tokenbus.Business, *userbus.Business) {
+	ctx := context.Background()
+	db := memdb.New()
+
+	userBus := userbus.NewBusiness(usermem.NewStore(db))
+	for _, email := range []string{"active@example.com", "inactive@example.com"} {
+		_, err := userBus.Create(ctx, userbus.NewUser{Name: email, Email: email})
+		require.NoError(t, err)
+	}
+
+	return tokenbus.NewBusiness(tokenmem.NewStore(db), userBus, ttl), userBus
+}
+
+func TestRotate(t *testing.T) {
+	business, _ := setup(t, time.Hour)
+
+	ctx := context.Background()
+	token, rt, err := business.Issue(ctx, 1)
+	require.NoError(t, err)
+	assert.NotContains(t, rt.TokenHash, token)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), rt.ExpiresAt, time.Minute)
+
+	next, rt, err := business.Rotate(ctx, token)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, next)
+	assert.Equal(t, 1, rt.UserID)
+
+	_, _, err = business.Rotate(ctx, "unknown")
+	assert.ErrorIs(t, err, tokenbus.ErrNotFound)
+}
+
+func TestRotateReused(t *testing.T) {
+	business, _ := setup(t, time.Hour)
+
+	ctx := context.Background()
+	token, _, err := business.Issue(ctx, 1)
+	require.NoError(t, err)
+
+	next, _, err := business.Rotate(ctx, token)
+	require.NoError(t, err)
+
+	// Presenting the old token again revokes the new one as well.
+	_, _, err = business.Rotate(ctx, token)
+	assert.ErrorIs(t, err, tokenbus.ErrRevoked)
+
+	_, _, err = business.Rotate(ctx, next)
+	assert.ErrorIs(t, err, tokenbus.ErrRevoked)
+}
+
+func TestRotateRefused(t *testing.T) {
+	ctx := context.Background()
+
+	business, _ := setup(t, -time.Minute)
+	token, _, err := business.Issue(ctx, 1)
+	require.NoError(t, err)
+
+	_, _, err = business.Rotate(ctx, token)
+	assert.ErrorIs(t, err, tokenbus.ErrExpired)
+
+	business, userBus := setup(t, time.Hour)
+	token, _, err = business.Issue(ctx, 2)
+	require.NoError(t, err)
+	require.NoError(t, userBus.Deactivate(ctx, 2))
+
+	_, _, err = business.Rotate(ctx, token)
+	assert.ErrorIs(t, err, userbus.ErrUserInactive)
+}
+
+func TestRevoke(t *testing.T) {
+	business, _ := setup(t, time.Hour)
+
+	ctx := context.Background()
+	token, _, err := business.Issue(ctx, 1)
+	require.NoError(t, err)
+
+	assert.NoError(t, business.Revoke(ctx, token))
+	assert.NoError(t, business.Revoke(ctx, token), "revoking twice must not fail")
+	assert.NoError(t, business.Revoke(ctx, "unknown"))
+
+	_, _, err = business.Rotate(ctx, token)
+	assert.ErrorIs(t, err, tokenbus.ErrRevoked)
+
+	other, _, err := business.Issue(ctx, 1)
+	require.NoError(t, err)
+	assert.NoError(t, business.RevokeUser(ctx, 1))
+
+	_, _, err = business.Rotate(ctx, other)
+	assert.ErrorIs(t, err, tokenbus.ErrRevoked)
+}
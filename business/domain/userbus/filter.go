@@ -0,0 +1,13 @@
+package userbus
+
+import "time"
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field is not filtered on.
+type QueryFilter struct {
+	Active           *bool
+	Name             *string
+	EmailDomain      *string
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
@@ -9,15 +9,24 @@ type User struct {
 	ID        int          `json:"id"`
 	Name      string       `json:"name"`
 	Email     string       `json:"email"`
+	Role      Role         `json:"role"`
 	Active    bool         `json:"active"`
 	CreatedAt sql.NullTime `json:"created_at"`
 	UpdatedAt sql.NullTime `json:"updated_at"`
+
+	// PasswordHash is the bcrypt hash of the password. It is empty for users
+	// created without one, who can only get tokens from the admin tool.
+	PasswordHash []byte `json:"-"`
 }
 
 // NewUser represents the input data required to create a new user.
 type NewUser struct {
-	Name  string
-	Email string
+	Name     string
+	Email    string
+	Password string
+
+	// Role defaults to RoleUser.
+	Role Role
 }
 
 // UpdateUser represents the input data required to update an existing user.
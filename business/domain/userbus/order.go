@@ -0,0 +1,14 @@
+package userbus
+
+import "TODO-list/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID        = "id"
+	OrderByName      = "name"
+	OrderByEmail     = "email"
+	OrderByCreatedAt = "created_at"
+)
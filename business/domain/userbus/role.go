@@ -0,0 +1,67 @@
+package userbus
+
+import "fmt"
+
+// The set of roles a user can have.
+var (
+	RoleAdmin = Role{"ADMIN"}
+	RoleUser  = Role{"USER"}
+)
+
+var roles = map[string]Role{
+	RoleAdmin.name: RoleAdmin,
+	RoleUser.name:  RoleUser,
+}
+
+// Role represents the role of a user. Admins manage the users and may act on
+// every project and task.
+type Role struct {
+	name string
+}
+
+// ParseRole parses the string value and returns a role if one exists.
+func ParseRole(value string) (Role, error) {
+	role, exists := roles[value]
+	if !exists {
+		return Role{}, fmt.Errorf("invalid role %q", value)
+	}
+
+	return role, nil
+}
+
+// MustParseRole parses the string value and returns a role if one exists.
+// If an error occurs the function panics.
+func MustParseRole(value string) Role {
+	role, err := ParseRole(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return role
+}
+
+// String returns the name of the role.
+func (r Role) String() string {
+	return r.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Role) Equal(r2 Role) bool {
+	return r.name == r2.name
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.name), nil
+}
+
+// UnmarshalText parses the name of a role.
+func (r *Role) UnmarshalText(data []byte) error {
+	role, err := ParseRole(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = role
+	return nil
+}
@@ -0,0 +1,48 @@
+package userdb
+
+import (
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/sqldb"
+	"strings"
+)
+
+// applyFilter appends the WHERE clause for the filter to the query and
+// returns the arguments for its placeholders. SQLite keeps dates as text with
+// the offset they were written in, so the bounds are passed in UTC like the
+// stored dates to compare them.
+func applyFilter(filter userbus.QueryFilter, buf *strings.Builder) []any {
+	var wc []string
+	var args []any
+
+	if filter.Active != nil {
+		wc = append(wc, "active = ?")
+		args = append(args, *filter.Active)
+	}
+
+	if filter.Name != nil {
+		wc = append(wc, "name LIKE ? ESCAPE '!'")
+		args = append(args, sqldb.EscapeLike(*filter.Name)+"%")
+	}
+
+	if filter.EmailDomain != nil {
+		wc = append(wc, "email LIKE ? ESCAPE '!'")
+		args = append(args, "%@"+sqldb.EscapeLike(*filter.EmailDomain))
+	}
+
+	if filter.StartCreatedDate != nil {
+		wc = append(wc, "created_at >= ?")
+		args = append(args, filter.StartCreatedDate.UTC())
+	}
+
+	if filter.EndCreatedDate != nil {
+		wc = append(wc, "created_at <= ?")
+		args = append(args, filter.EndCreatedDate.UTC())
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+
+	return args
+}
@@ -0,0 +1,45 @@
+package userdb
+
+import (
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"fmt"
+	"strings"
+)
+
+var orderByFields = map[string]string{
+	userbus.OrderByID:        "id",
+	userbus.OrderByName:      "name",
+	userbus.OrderByEmail:     "email",
+	userbus.OrderByCreatedAt: "created_at",
+}
+
+// orderByClause returns the ORDER BY clause for the list. The id is added as
+// the last key when missing so pages are stable when the other fields tie.
+func orderByClause(orderBy []order.By) (string, error) {
+	var fields []string
+	var hasID bool
+	for _, by := range orderBy {
+		field, err := orderByField(by)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, field)
+		hasID = hasID || by.Field == userbus.OrderByID
+	}
+
+	if !hasID {
+		fields = append(fields, "id")
+	}
+
+	return " ORDER BY " + strings.Join(fields, ", "), nil
+}
+
+func orderByField(by order.By) (string, error) {
+	column, exists := orderByFields[by.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", by.Field)
+	}
+
+	return column + " " + by.Direction, nil
+}
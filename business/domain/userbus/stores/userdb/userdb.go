@@ -0,0 +1,173 @@
+// Package userdb contains user related CRUD functionality for MySQL.
+package userdb
+
+import (
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Store manages the set of APIs for user database access.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new user into the database and returns its ID.
+func (s *Store) Create(ctx context.Context, usr userbus.User) (int, error) {
+	query := "INSERT INTO users (name, email, password_hash, role, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := sqldb.ExecContext(ctx, s.db, query, usr.Name, usr.Email, usr.PasswordHash, usr.Role.String(), usr.Active, usr.CreatedAt, usr.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return 0, fmt.Errorf("exec: %w", userbus.ErrUniqueEmail)
+		}
+		return 0, err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastInsertID), nil
+}
+
+// Update replaces a user document in the database.
+func (s *Store) Update(ctx context.Context, usr userbus.User) error {
+	query := "UPDATE users SET name = ?, email = ?, password_hash = ?, role = ?, active = ?, updated_at = ? WHERE id = ?"
+	result, err := sqldb.ExecContext(ctx, s.db, query, usr.Name, usr.Email, usr.PasswordHash, usr.Role.String(), usr.Active, usr.UpdatedAt, usr.ID)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("exec: %w", userbus.ErrUniqueEmail)
+		}
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// Query retrieves a page of the users matching the filter from the database.
+func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy []order.By, page page.Page) ([]userbus.User, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users")
+
+	args := applyFilter(filter, &buf)
+
+	clause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(clause)
+
+	buf.WriteString(" LIMIT ? OFFSET ?")
+	args = append(args, page.RowsPerPage(), page.Offset())
+
+	rows, err := sqldb.QueryContext(ctx, s.db, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []userbus.User
+	for rows.Next() {
+		busUser, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, busUser)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Count returns the total number of users matching the filter.
+func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT COUNT(1) FROM users")
+
+	args := applyFilter(filter, &buf)
+
+	var count int
+	if err := sqldb.QueryRowContext(ctx, s.db, buf.String(), args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// QueryByID retrieves a specific user by their ID from the database.
+func (s *Store) QueryByID(ctx context.Context, id int) (userbus.User, error) {
+	query := "SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users WHERE id = ?"
+	row := sqldb.QueryRowContext(ctx, s.db, query, id)
+
+	busUser, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.User{}, fmt.Errorf("scan: %w", userbus.ErrNotFound)
+		}
+		return userbus.User{}, err
+	}
+
+	return busUser, nil
+}
+
+// QueryByEmail retrieves a specific user by their email from the database.
+func (s *Store) QueryByEmail(ctx context.Context, email string) (userbus.User, error) {
+	query := "SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users WHERE email = ?"
+	row := sqldb.QueryRowContext(ctx, s.db, query, email)
+
+	busUser, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.User{}, fmt.Errorf("scan: %w", userbus.ErrNotFound)
+		}
+		return userbus.User{}, err
+	}
+
+	return busUser, nil
+}
+
+// scanUser reads the columns selected by the queries into a user.
+func scanUser(row interface{ Scan(dest ...any) error }) (userbus.User, error) {
+	var busUser userbus.User
+	var role string
+
+	err := row.Scan(&busUser.ID, &busUser.Name, &busUser.Email, &busUser.PasswordHash, &role, &busUser.Active, &busUser.CreatedAt, &busUser.UpdatedAt)
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	if busUser.Role, err = userbus.ParseRole(role); err != nil {
+		return userbus.User{}, fmt.Errorf("userID[%d]: %w", busUser.ID, err)
+	}
+
+	return busUser, nil
+}
+
+// checkAffected reports ErrNotFound when the statement did not match a row.
+func checkAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("exec: %w", userbus.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package userdb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/userdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	db    *sql.DB
+	mock  sqlmock.Sqlmock
+	store *userdb.Store
+)
+
+func setupMockDB(t *testing.T) {
+	var err error
+	db, mock, err = sqlmock.New()
+	assert.NoError(t, err)
+	store = userdb.NewStore(db)
+}
+
+func mockUserRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "name", "email", "password_hash", "role", "active", "created_at", "updated_at"}).
+		AddRow(1, "User 1", "user1@example.com", []byte("hash"), "ADMIN", true, time.Now(), time.Now()).
+		AddRow(2, "User 2", "user2@example.com", nil, "USER", true, time.Now(), time.Now())
+}
+
+func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs("New User", "newuser@example.com", []byte("hash"), "USER", true, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	ctx := context.Background()
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+	usr := userbus.User{Name: "New User", Email: "newuser@example.com", PasswordHash: []byte("hash"), Role: userbus.RoleUser, Active: true, CreatedAt: now, UpdatedAt: now}
+	id, err := store.Create(ctx, usr)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assertMockExpectations(t, mock)
+}
+
+func TestQuery(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users ORDER BY id ASC LIMIT \\? OFFSET \\?$").
+		WithArgs(10, 0).
+		WillReturnRows(mockUserRows())
+
+	ctx := context.Background()
+	users, err := store.Query(ctx, userbus.QueryFilter{}, []order.By{userbus.DefaultOrderBy}, page.MustParse("1", "10"))
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "User 1", users[0].Name)
+	assert.Equal(t, "user1@example.com", users[0].Email)
+	assert.True(t, users[0].Active)
+	assert.True(t, users[0].CreatedAt.Valid)
+	assert.True(t, users[0].UpdatedAt.Valid)
+	assert.True(t, users[0].CreatedAt.Time.After(time.Now().Add(-time.Hour)))
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryFilter(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	active := true
+	name := "Us"
+	domain := "example.com"
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := userbus.QueryFilter{Active: &active, Name: &name, EmailDomain: &domain, StartCreatedDate: &start}
+	orderBy := []order.By{order.NewBy(userbus.OrderByEmail, order.DESC)}
+
+	mock.ExpectQuery("^SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users "+
+		"WHERE active = \\? AND name LIKE \\? ESCAPE '!' AND email LIKE \\? ESCAPE '!' AND created_at >= \\? ORDER BY email DESC, id LIMIT \\? OFFSET \\?$").
+		WithArgs(true, "Us%", "%@example.com", start, 5, 0).
+		WillReturnRows(mockUserRows())
+
+	mock.ExpectQuery("^SELECT COUNT\\(1\\) FROM users WHERE active = \\? AND name LIKE \\? ESCAPE '!' AND email LIKE \\? ESCAPE '!' AND created_at >= \\?$").
+		WithArgs(true, "Us%", "%@example.com", start).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	ctx := context.Background()
+	users, err := store.Query(ctx, filter, orderBy, page.MustParse("1", "5"))
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	count, err := store.Count(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryFilterLiteral(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	name := "a_b%"
+	domain := "ex!ample.com"
+	filter := userbus.QueryFilter{Name: &name, EmailDomain: &domain}
+
+	mock.ExpectQuery("^SELECT COUNT\\(1\\) FROM users WHERE name LIKE \\? ESCAPE '!' AND email LIKE \\? ESCAPE '!'$").
+		WithArgs("a!_b!%%", "%@ex!!ample.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	count, err := store.Count(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryDatesInUTC(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, saoPaulo)
+	end := time.Date(2026, 10, 18, 21, 0, 0, 0, saoPaulo)
+	filter := userbus.QueryFilter{StartCreatedDate: &start, EndCreatedDate: &end}
+
+	mock.ExpectQuery("^SELECT COUNT\\(1\\) FROM users WHERE created_at >= \\? AND created_at <= \\?$").
+		WithArgs(time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := store.Count(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryByID(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	row := sqlmock.NewRows([]string{"id", "name", "email", "password_hash", "role", "active", "created_at", "updated_at"}).
+		AddRow(1, "User 1", "user1@example.com", []byte("hash"), "ADMIN", true, time.Now(), time.Now())
+
+	mock.ExpectQuery("SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users WHERE id = ?").
+		WithArgs(1).
+		WillReturnRows(row)
+
+	ctx := context.Background()
+	user, err := store.QueryByID(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, userbus.RoleAdmin, user.Role)
+	assert.Equal(t, "User 1", user.Name)
+	assert.Equal(t, "user1@example.com", user.Email)
+	assert.True(t, user.Active)
+	assert.True(t, user.CreatedAt.Valid)
+	assert.True(t, user.UpdatedAt.Valid)
+	assert.True(t, user.CreatedAt.Time.After(time.Now().Add(-time.Hour)))
+	assertMockExpectations(t, mock)
+}
+
+func TestQueryByEmail(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	row := sqlmock.NewRows([]string{"id", "name", "email", "password_hash", "role", "active", "created_at", "updated_at"}).
+		AddRow(1, "User 1", "user1@example.com", []byte("hash"), "ADMIN", true, time.Now(), time.Now())
+
+	mock.ExpectQuery("SELECT id, name, email, password_hash, role, active, created_at, updated_at FROM users WHERE email = ?").
+		WithArgs("user1@example.com").
+		WillReturnRows(row)
+
+	ctx := context.Background()
+	user, err := store.QueryByEmail(ctx, "user1@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "User 1", user.Name)
+	assert.Equal(t, "user1@example.com", user.Email)
+	assert.True(t, user.Active)
+	assert.True(t, user.CreatedAt.Valid)
+	assert.True(t, user.UpdatedAt.Valid)
+	assert.True(t, user.CreatedAt.Time.After(time.Now().Add(-time.Hour)))
+	assertMockExpectations(t, mock)
+}
+
+func TestUpdate(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^UPDATE users SET name = \\?, email = \\?, password_hash = \\?, role = \\?, active = \\?, updated_at = \\? WHERE id = \\?$").
+		WithArgs("Updated Name", "updated@example.com", []byte(nil), "ADMIN", false, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	usr := userbus.User{ID: 1, Name: "Updated Name", Email: "updated@example.com", Role: userbus.RoleAdmin, Active: false, UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+	err := store.Update(ctx, usr)
+
+	assert.NoError(t, err)
+	assertMockExpectations(t, mock)
+}
+
+func TestUpdateNotFound(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("^UPDATE users SET name = \\?, email = \\?, password_hash = \\?, role = \\?, active = \\?, updated_at = \\? WHERE id = \\?$").
+		WithArgs("Updated Name", "updated@example.com", []byte(nil), "USER", true, sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+	usr := userbus.User{ID: 9, Name: "Updated Name", Email: "updated@example.com", Role: userbus.RoleUser, Active: true}
+	err := store.Update(ctx, usr)
+
+	assert.ErrorIs(t, err, userbus.ErrNotFound)
+	assertMockExpectations(t, mock)
+}
+
+func TestCreateUniqueEmail(t *testing.T) {
+	setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'user@example.com' for key 'email'"})
+
+	ctx := context.Background()
+	_, err := store.Create(ctx, userbus.User{Name: "User", Email: "user@example.com", Active: true})
+
+	assert.ErrorIs(t, err, userbus.ErrUniqueEmail)
+	assertMockExpectations(t, mock)
+}
@@ -0,0 +1,32 @@
+package usermem
+
+import (
+	"TODO-list/business/domain/userbus"
+	"strings"
+)
+
+// match reports whether the user satisfies every field set in the filter,
+// mirroring the WHERE clause the SQL store builds.
+func match(filter userbus.QueryFilter, usr userbus.User) bool {
+	if filter.Active != nil && usr.Active != *filter.Active {
+		return false
+	}
+
+	if filter.Name != nil && !strings.HasPrefix(strings.ToLower(usr.Name), strings.ToLower(*filter.Name)) {
+		return false
+	}
+
+	if filter.EmailDomain != nil && !strings.HasSuffix(strings.ToLower(usr.Email), "@"+strings.ToLower(*filter.EmailDomain)) {
+		return false
+	}
+
+	if filter.StartCreatedDate != nil && (!usr.CreatedAt.Valid || usr.CreatedAt.Time.Before(*filter.StartCreatedDate)) {
+		return false
+	}
+
+	if filter.EndCreatedDate != nil && (!usr.CreatedAt.Valid || usr.CreatedAt.Time.After(*filter.EndCreatedDate)) {
+		return false
+	}
+
+	return true
+}
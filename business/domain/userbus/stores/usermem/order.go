@@ -0,0 +1,74 @@
+package usermem
+
+import (
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/order"
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+var orderByFields = map[string]func(a, b userbus.User) int{
+	userbus.OrderByID: func(a, b userbus.User) int {
+		return cmp.Compare(a.ID, b.ID)
+	},
+	userbus.OrderByName: func(a, b userbus.User) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	userbus.OrderByEmail: func(a, b userbus.User) int {
+		return strings.Compare(a.Email, b.Email)
+	},
+	userbus.OrderByCreatedAt: func(a, b userbus.User) int {
+		return compareNull(a.CreatedAt.Valid, b.CreatedAt.Valid, func() int {
+			return a.CreatedAt.Time.Compare(b.CreatedAt.Time)
+		})
+	},
+}
+
+// compareUsers returns the comparison function for the list. Like the SQL
+// store, the id breaks the ties so the pages are stable.
+func compareUsers(orderBy []order.By) (func(a, b userbus.User) int, error) {
+	type key struct {
+		compare func(a, b userbus.User) int
+		desc    bool
+	}
+
+	keys := make([]key, 0, len(orderBy)+1)
+	for _, by := range orderBy {
+		compare, exists := orderByFields[by.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q does not exist", by.Field)
+		}
+		keys = append(keys, key{compare: compare, desc: by.Direction == order.DESC})
+	}
+	keys = append(keys, key{compare: orderByFields[userbus.OrderByID]})
+
+	f := func(a, b userbus.User) int {
+		for _, k := range keys {
+			c := k.compare(a, b)
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	return f, nil
+}
+
+// compareNull sorts NULL values first, the way MySQL and SQLite do in
+// ascending order.
+func compareNull(aValid bool, bValid bool, compare func() int) int {
+	switch {
+	case aValid && bValid:
+		return compare()
+	case aValid:
+		return 1
+	case bValid:
+		return -1
+	}
+	return 0
+}
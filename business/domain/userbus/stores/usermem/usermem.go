@@ -0,0 +1,145 @@
+// Package usermem contains user related CRUD functionality held in memory.
+package usermem
+
+import (
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"context"
+	"fmt"
+	"slices"
+)
+
+const table = "users"
+
+// Store manages the set of APIs for user in-memory access.
+type Store struct {
+	db *memdb.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(db *memdb.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new user and returns its ID. The email must be unique.
+func (s *Store) Create(ctx context.Context, usr userbus.User) (int, error) {
+	var id int
+	err := s.db.Update(func(tx *memdb.Tx) error {
+		if err := checkEmail(tx, usr); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = tx.Insert(table, usr)
+		return err
+	})
+
+	return id, err
+}
+
+// Update replaces a stored user. The email must remain unique.
+func (s *Store) Update(ctx context.Context, usr userbus.User) error {
+	return s.db.Update(func(tx *memdb.Tx) error {
+		if err := checkEmail(tx, usr); err != nil {
+			return err
+		}
+
+		exists, err := tx.Replace(table, usr.ID, usr)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return userbus.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// Query retrieves a page of the users matching the filter.
+func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy []order.By, page page.Page) ([]userbus.User, error) {
+	compare, err := compareUsers(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	users := s.filter(filter)
+	slices.SortFunc(users, compare)
+
+	start := min(page.Offset(), len(users))
+	end := min(start+page.RowsPerPage(), len(users))
+
+	return users[start:end], nil
+}
+
+// Count returns the total number of users matching the filter.
+func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	return len(s.filter(filter)), nil
+}
+
+// QueryByID retrieves a specific user by their ID.
+func (s *Store) QueryByID(ctx context.Context, id int) (userbus.User, error) {
+	var usr userbus.User
+	err := s.db.View(func(tx *memdb.Tx) error {
+		v, exists := tx.Get(table, id)
+		if !exists {
+			return userbus.ErrNotFound
+		}
+
+		usr = toBusUser(id, v)
+		return nil
+	})
+
+	return usr, err
+}
+
+// QueryByEmail retrieves a specific user by their email.
+func (s *Store) QueryByEmail(ctx context.Context, email string) (userbus.User, error) {
+	var usr userbus.User
+	err := s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			if u := toBusUser(r.ID, r.Value); u.Email == email {
+				usr = u
+				return nil
+			}
+		}
+
+		return userbus.ErrNotFound
+	})
+
+	return usr, err
+}
+
+// filter returns the users matching the filter in id order.
+func (s *Store) filter(filter userbus.QueryFilter) []userbus.User {
+	var users []userbus.User
+	s.db.View(func(tx *memdb.Tx) error {
+		for _, r := range tx.Rows(table) {
+			if usr := toBusUser(r.ID, r.Value); match(filter, usr) {
+				users = append(users, usr)
+			}
+		}
+		return nil
+	})
+
+	return users
+}
+
+// checkEmail enforces the unique constraint on the email column.
+func checkEmail(tx *memdb.Tx, usr userbus.User) error {
+	for _, r := range tx.Rows(table) {
+		if r.ID != usr.ID && toBusUser(r.ID, r.Value).Email == usr.Email {
+			return fmt.Errorf("email[%s]: %w", usr.Email, userbus.ErrUniqueEmail)
+		}
+	}
+
+	return nil
+}
+
+func toBusUser(id int, v any) userbus.User {
+	usr := v.(userbus.User)
+	usr.ID = id
+	return usr
+}
@@ -1,108 +1,191 @@
 package userbus
 
 import (
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound        = errors.New("user not found")
+	ErrUniqueEmail     = errors.New("email is not unique")
+	ErrUserInactive    = errors.New("user is not active")
+	ErrAuthFailure     = errors.New("authentication failed")
+	ErrPasswordTooLong = errors.New("password is longer than 72 bytes")
 )
 
+// dummyHash is compared against when there is no password to check, so every
+// failed login costs the same bcrypt work and the response time does not
+// tell which emails are registered.
+var dummyHash = []byte("$2a$10$CUckI1q30tNzeriXrpAJLeuW/3q4SZV1L4mbUio/fnTmYhvHtZMGC")
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, usr User) (int, error)
+	Update(ctx context.Context, usr User) error
+	Query(ctx context.Context, filter QueryFilter, orderBy []order.By, page page.Page) ([]User, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, id int) (User, error)
+	QueryByEmail(ctx context.Context, email string) (User, error)
+}
+
 // Business handles business logic and persistence of user-related operations.
 type Business struct {
-	db *sql.DB
+	storer Storer
 }
 
-// NewBusiness creates a new instance of Business with the provided database connection.
-func NewBusiness(db *sql.DB) *Business {
-	return &Business{db: db}
+// NewBusiness creates a new instance of Business with the provided storer.
+func NewBusiness(storer Storer) *Business {
+	return &Business{storer: storer}
 }
 
 // Create inserts a new user into the database and returns the created user.
 func (s *Business) Create(ctx context.Context, nu NewUser) (User, error) {
-	createdAt := sql.NullTime{Time: time.Now(), Valid: true}
-	updatedAt := sql.NullTime{Time: createdAt.Time, Valid: true}
-	query := "INSERT INTO users (name, email, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
-	result, err := s.db.ExecContext(ctx, query, nu.Name, nu.Email, true, createdAt, updatedAt)
-	if err != nil {
-		return User{}, err
-	}
+	now := nowUTC()
 
-	lastInsertID, err := result.LastInsertId()
-	if err != nil {
-		return User{}, err
+	role := nu.Role
+	if role == (Role{}) {
+		role = RoleUser
 	}
 
-	return User{
-		ID:        int(lastInsertID),
+	usr := User{
 		Name:      nu.Name,
 		Email:     nu.Email,
+		Role:      role,
 		Active:    true,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-	}, nil
-}
-
-// Query retrieves all users from the database and returns them as a slice of User structs.
-func (s *Business) Query(ctx context.Context) ([]User, error) {
-	query := "SELECT id, name, email, active, created_at, updated_at FROM users"
-	rows, err := s.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
+		CreatedAt: sql.NullTime{Time: now, Valid: true},
+		UpdatedAt: sql.NullTime{Time: now, Valid: true},
 	}
-	defer rows.Close()
 
-	var users []User
-	for rows.Next() {
-		var busUser User
-
-		err := rows.Scan(&busUser.ID, &busUser.Name, &busUser.Email, &busUser.Active, &busUser.CreatedAt, &busUser.UpdatedAt)
+	if nu.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(nu.Password), bcrypt.DefaultCost)
 		if err != nil {
-			return nil, err
+			if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+				return User{}, fmt.Errorf("generating password hash: %w", ErrPasswordTooLong)
+			}
+			return User{}, fmt.Errorf("generating password hash: %w", err)
 		}
-		users = append(users, busUser)
+		usr.PasswordHash = hash
+	}
+
+	id, err := s.storer.Create(ctx, usr)
+	if err != nil {
+		return User{}, fmt.Errorf("create: %w", err)
 	}
+	usr.ID = id
+
+	return usr, nil
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+// Query retrieves a page of the users matching the filter in the given order.
+func (s *Business) Query(ctx context.Context, filter QueryFilter, orderBy []order.By, page page.Page) ([]User, error) {
+	users, err := s.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
 	}
 
 	return users, nil
 }
 
+// Count returns the total number of users matching the filter.
+func (s *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	n, err := s.storer.Count(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+
+	return n, nil
+}
+
 // QueryById retrieves a specific user by their ID from the database.
 func (s *Business) QueryById(ctx context.Context, id int) (User, error) {
-	query := "SELECT id, name, email, active, created_at, updated_at FROM users WHERE id = ?"
-	row := s.db.QueryRowContext(ctx, query, id)
-
-	var busUser User
-	err := row.Scan(&busUser.ID, &busUser.Name, &busUser.Email, &busUser.Active, &busUser.CreatedAt, &busUser.UpdatedAt)
+	usr, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return User{}, err
+		return User{}, fmt.Errorf("query: userID[%d]: %w", id, err)
 	}
 
-	return busUser, nil
+	return usr, nil
 }
 
 // QueryByEmail retrieves a specific user by their email from the database.
 func (s *Business) QueryByEmail(ctx context.Context, email string) (User, error) {
-	query := "SELECT id, name, email, active, created_at, updated_at FROM users WHERE email = ? "
-	row := s.db.QueryRowContext(ctx, query, email)
+	usr, err := s.storer.QueryByEmail(ctx, email)
+	if err != nil {
+		return User{}, fmt.Errorf("query: email[%s]: %w", email, err)
+	}
+
+	return usr, nil
+}
 
-	var busUser User
-	err := row.Scan(&busUser.ID, &busUser.Name, &busUser.Email, &busUser.Active, &busUser.CreatedAt, &busUser.UpdatedAt)
+// Authenticate checks the email and password of a user. Unknown emails, users
+// without a password and wrong passwords all fail with ErrAuthFailure after
+// the same bcrypt work, so the caller can not tell which emails are
+// registered. Inactive users are refused with ErrUserInactive once the
+// password matched.
+func (s *Business) Authenticate(ctx context.Context, email string, password string) (User, error) {
+	usr, err := s.storer.QueryByEmail(ctx, email)
 	if err != nil {
-		return User{}, err
+		if errors.Is(err, ErrNotFound) {
+			bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+			return User{}, ErrAuthFailure
+		}
+		return User{}, fmt.Errorf("query: email[%s]: %w", email, err)
+	}
+
+	if len(usr.PasswordHash) == 0 {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return User{}, ErrAuthFailure
+	}
+
+	if err := bcrypt.CompareHashAndPassword(usr.PasswordHash, []byte(password)); err != nil {
+		return User{}, ErrAuthFailure
+	}
+
+	if !usr.Active {
+		return User{}, fmt.Errorf("userID[%d]: %w", usr.ID, ErrUserInactive)
 	}
 
-	return busUser, nil
+	return usr, nil
 }
 
 // Update modifies an existing user's information in the database.
 func (s *Business) Update(ctx context.Context, id int, uu UpdateUser) error {
-	UpdatedAt := sql.NullTime{Time: time.Now(), Valid: true}
-	query := "UPDATE users SET name = ?, email = ?, updated_at = ? WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, uu.Name, uu.Email, UpdatedAt, id)
+	usr, err := s.storer.QueryByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("query: userID[%d]: %w", id, err)
+	}
+
+	usr.Name = uu.Name
+	usr.Email = uu.Email
+	usr.UpdatedAt = sql.NullTime{Time: nowUTC(), Valid: true}
+
+	if err := s.storer.Update(ctx, usr); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	return nil
+}
+
+// SetRole changes the role of a user.
+func (s *Business) SetRole(ctx context.Context, id int, role Role) error {
+	usr, err := s.storer.QueryByID(ctx, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("query: userID[%d]: %w", id, err)
+	}
+
+	usr.Role = role
+	usr.UpdatedAt = sql.NullTime{Time: nowUTC(), Valid: true}
+
+	if err := s.storer.Update(ctx, usr); err != nil {
+		return fmt.Errorf("set role: %w", err)
 	}
 
 	return nil
@@ -110,8 +193,24 @@ func (s *Business) Update(ctx context.Context, id int, uu UpdateUser) error {
 
 // Deactivate sets a user's status to inactive (false) in the database.
 func (s *Business) Deactivate(ctx context.Context, id int) error {
-	UpdatedAt := sql.NullTime{Time: time.Now(), Valid: true}
-	query := "UPDATE users SET active = false, updated_at = ? WHERE id = ?"
-	_, err := s.db.ExecContext(ctx, query, UpdatedAt, id)
-	return err
+	usr, err := s.storer.QueryByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("query: userID[%d]: %w", id, err)
+	}
+
+	usr.Active = false
+	usr.UpdatedAt = sql.NullTime{Time: nowUTC(), Valid: true}
+
+	if err := s.storer.Update(ctx, usr); err != nil {
+		return fmt.Errorf("deactivate: %w", err)
+	}
+
+	return nil
+}
+
+// nowUTC returns the current time in UTC to the second. SQLite compares the
+// stored times as text, so they all share one offset for the date filters to
+// work.
+func nowUTC() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
 }
@@ -2,46 +2,21 @@ package userbus_test
 
 import (
 	"context"
-	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
 	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/usermem"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
-var (
-	db       *sql.DB
-	mock     sqlmock.Sqlmock
-	business *userbus.Business
-)
-
-func setupMockDB(t *testing.T) {
-	var err error
-	db, mock, err = sqlmock.New()
-	assert.NoError(t, err)
-	business = userbus.NewBusiness(db)
-}
-
-func mockUserRows() *sqlmock.Rows {
-	return sqlmock.NewRows([]string{"id", "name", "email", "active", "created_at", "updated_at"}).
-		AddRow(1, "User 1", "user1@example.com", true, time.Now(), time.Now()).
-		AddRow(2, "User 2", "user2@example.com", true, time.Now(), time.Now())
-}
-
-func assertMockExpectations(t *testing.T, mock sqlmock.Sqlmock) {
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
-
 func TestCreate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
-
-	mock.ExpectExec("INSERT INTO users").
-		WithArgs("New User", "newuser@example.com", true, sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
 	ctx := context.Background()
 	newUser := userbus.NewUser{Name: "New User", Email: "newuser@example.com"}
@@ -55,105 +30,151 @@ func TestCreate(t *testing.T) {
 	assert.True(t, user.CreatedAt.Valid)
 	assert.True(t, user.UpdatedAt.Valid)
 	assert.True(t, user.CreatedAt.Time.After(time.Now().Add(-time.Hour)))
-	assertMockExpectations(t, mock)
 }
 
-func TestQuery(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
-
-	mock.ExpectQuery("SELECT id, name, email, active, created_at, updated_at FROM users").
-		WillReturnRows(mockUserRows())
+func TestUpdate(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
 	ctx := context.Background()
-	users, err := business.Query(ctx)
+	user, err := business.Create(ctx, userbus.NewUser{Name: "User 1", Email: "user1@example.com"})
+	assert.NoError(t, err)
+
+	err = business.Update(ctx, user.ID, userbus.UpdateUser{Name: "Updated Name", Email: "updated@example.com"})
+	assert.NoError(t, err)
 
+	user, err = business.QueryByEmail(ctx, "updated@example.com")
 	assert.NoError(t, err)
-	assert.Len(t, users, 2)
-	assert.Equal(t, "User 1", users[0].Name)
-	assert.Equal(t, "user1@example.com", users[0].Email)
-	assert.True(t, users[0].Active)
-	assert.True(t, users[0].CreatedAt.Valid)
-	assert.True(t, users[0].UpdatedAt.Valid)
-	assert.True(t, users[0].CreatedAt.Time.After(time.Now().Add(-time.Hour)))
-	assertMockExpectations(t, mock)
+	assert.Equal(t, "Updated Name", user.Name)
+	assert.True(t, user.Active)
 }
 
-func TestQueryByID(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestUpdateNotFound(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
-	row := sqlmock.NewRows([]string{"id", "name", "email", "active", "created_at", "updated_at"}).
-		AddRow(1, "User 1", "user1@example.com", true, time.Now(), time.Now())
+	err := business.Update(context.Background(), 99, userbus.UpdateUser{Name: "Nobody"})
+	assert.ErrorIs(t, err, userbus.ErrNotFound)
+}
 
-	mock.ExpectQuery("SELECT id, name, email, active, created_at, updated_at FROM users WHERE id = ?").
-		WithArgs(1).
-		WillReturnRows(row)
+func TestDeactivate(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
 	ctx := context.Background()
-	user, err := business.QueryById(ctx, 1)
+	user, err := business.Create(ctx, userbus.NewUser{Name: "User 1", Email: "user1@example.com"})
+	assert.NoError(t, err)
+
+	err = business.Deactivate(ctx, user.ID)
+	assert.NoError(t, err)
 
+	user, err = business.QueryById(ctx, user.ID)
 	assert.NoError(t, err)
+	assert.False(t, user.Active)
 	assert.Equal(t, "User 1", user.Name)
-	assert.Equal(t, "user1@example.com", user.Email)
-	assert.True(t, user.Active)
-	assert.True(t, user.CreatedAt.Valid)
-	assert.True(t, user.UpdatedAt.Valid)
-	assert.True(t, user.CreatedAt.Time.After(time.Now().Add(-time.Hour)))
-	assertMockExpectations(t, mock)
 }
 
-func TestQueryByEmail(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+func TestCreateUniqueEmail(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
+
+	ctx := context.Background()
+	_, err := business.Create(ctx, userbus.NewUser{Name: "User 1", Email: "user1@example.com"})
+	assert.NoError(t, err)
 
-	row := sqlmock.NewRows([]string{"id", "name", "email", "active", "created_at", "updated_at"}).
-		AddRow(1, "User 1", "user1@example.com", true, time.Now(), time.Now())
+	_, err = business.Create(ctx, userbus.NewUser{Name: "User 2", Email: "user1@example.com"})
+	assert.ErrorIs(t, err, userbus.ErrUniqueEmail)
+}
 
-	mock.ExpectQuery("SELECT id, name, email, active, created_at, updated_at FROM users WHERE email = ?").
-		WithArgs("user1@example.com").
-		WillReturnRows(row)
+func TestQuery(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
 	ctx := context.Background()
-	user, err := business.QueryByEmail(ctx, "user1@example.com")
+	for _, nu := range []userbus.NewUser{
+		{Name: "Ana", Email: "ana@example.com"},
+		{Name: "Bruno", Email: "bruno@other.com"},
+		{Name: "Amanda", Email: "amanda@example.com"},
+		{Name: "Carla", Email: "carla@example.com"},
+	} {
+		_, err := business.Create(ctx, nu)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, business.Deactivate(ctx, 4))
+
+	name := "a"
+	users, err := business.Query(ctx, userbus.QueryFilter{Name: &name}, []order.By{order.NewBy(userbus.OrderByName, order.ASC)}, page.MustParse("1", "10"))
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, "Amanda", users[0].Name)
+		assert.Equal(t, "Ana", users[1].Name)
+	}
+
+	active := true
+	domain := "example.com"
+	filter := userbus.QueryFilter{Active: &active, EmailDomain: &domain}
+	users, err = business.Query(ctx, filter, []order.By{order.NewBy(userbus.OrderByID, order.DESC)}, page.MustParse("2", "1"))
+	assert.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, 1, users[0].ID)
+	}
 
+	count, err := business.Count(ctx, filter)
 	assert.NoError(t, err)
-	assert.Equal(t, "User 1", user.Name)
-	assert.Equal(t, "user1@example.com", user.Email)
-	assert.True(t, user.Active)
-	assert.True(t, user.CreatedAt.Valid)
-	assert.True(t, user.UpdatedAt.Valid)
-	assert.True(t, user.CreatedAt.Time.After(time.Now().Add(-time.Hour)))
-	assertMockExpectations(t, mock)
+	assert.Equal(t, 2, count)
 }
 
-func TestUpdate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
-
-	mock.ExpectExec("^UPDATE users SET name = \\?, email = \\?, updated_at = \\? WHERE id = \\?$").
-		WithArgs("Updated Name", "updated@example.com", sqlmock.AnyArg(), 1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+func TestAuthenticate(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
 	ctx := context.Background()
-	updateUser := userbus.UpdateUser{Name: "Updated Name", Email: "updated@example.com"}
-	err := business.Update(ctx, 1, updateUser)
+	user, err := business.Create(ctx, userbus.NewUser{Name: "User", Email: "user@example.com", Password: "gophers!"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte("gophers!"), user.PasswordHash)
 
+	_, err = business.Create(ctx, userbus.NewUser{Name: "No Password", Email: "nopass@example.com"})
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
-}
 
-func TestDeactivate(t *testing.T) {
-	setupMockDB(t)
-	defer db.Close()
+	_, err = business.Create(ctx, userbus.NewUser{Name: "Long", Email: "long@example.com", Password: strings.Repeat("🔑", 20)})
+	assert.ErrorIs(t, err, userbus.ErrPasswordTooLong, "bcrypt reads at most 72 bytes")
 
-	mock.ExpectExec("^UPDATE users SET active = false, updated_at = \\? WHERE id = \\?$").
-		WithArgs(sqlmock.AnyArg(), 1).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	got, err := business.Authenticate(ctx, "user@example.com", "gophers!")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+
+	for _, tt := range []struct{ email, password string }{
+		{"user@example.com", "wrong"},
+		{"unknown@example.com", "gophers!"},
+		{"nopass@example.com", ""},
+	} {
+		_, err = business.Authenticate(ctx, tt.email, tt.password)
+		assert.ErrorIs(t, err, userbus.ErrAuthFailure, tt.email)
+	}
+
+	// An unknown email runs a bcrypt comparison like a wrong password does,
+	// which takes milliseconds where a lookup alone takes microseconds.
+	start := time.Now()
+	business.Authenticate(ctx, "user@example.com", "wrong")
+	wrong := time.Since(start)
+
+	start = time.Now()
+	business.Authenticate(ctx, "unknown@example.com", "wrong")
+	assert.Greater(t, time.Since(start), wrong/10, "unknown emails cost a bcrypt comparison too")
+
+	assert.NoError(t, business.Deactivate(ctx, user.ID))
+	_, err = business.Authenticate(ctx, "user@example.com", "gophers!")
+	assert.ErrorIs(t, err, userbus.ErrUserInactive)
+}
+
+func TestSetRole(t *testing.T) {
+	business := userbus.NewBusiness(usermem.NewStore(memdb.New()))
 
 	ctx := context.Background()
-	err := business.Deactivate(ctx, 1)
+	user, err := business.Create(ctx, userbus.NewUser{Name: "User", Email: "user@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, userbus.RoleUser, user.Role, "new users default to the user role")
+
+	assert.NoError(t, business.SetRole(ctx, user.ID, userbus.RoleAdmin))
 
+	user, err = business.QueryById(ctx, user.ID)
 	assert.NoError(t, err)
-	assertMockExpectations(t, mock)
+	assert.Equal(t, userbus.RoleAdmin, user.Role)
+
+	_, err = userbus.ParseRole("ROOT")
+	assert.Error(t, err)
 }
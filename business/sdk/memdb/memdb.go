@@ -0,0 +1,227 @@
+// Package memdb provides a thread-safe in-memory database that the domain
+// memory stores share, the same way the database stores share a *sql.DB.
+package memdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Set of error variables for the in-memory database.
+var (
+	ErrReadOnly   = errors.New("write attempted in a read-only transaction")
+	ErrForeignKey = errors.New("foreign key constraint fails")
+	ErrDuplicate  = errors.New("duplicate entry")
+)
+
+// Ref declares that a row points at the row with the specified ID in another
+// table. Inserts and updates fail when the referenced row does not exist, and
+// deletes fail while a row is still referenced.
+type Ref struct {
+	Table string
+	ID    int
+}
+
+// Row represents a stored value along with its generated ID.
+type Row struct {
+	ID    int
+	Value any
+}
+
+type row struct {
+	value any
+	refs  []Ref
+}
+
+type table struct {
+	lastID int
+	rows   map[int]row
+}
+
+// DB represents a set of named tables held in memory.
+type DB struct {
+	mu     sync.RWMutex
+	tables map[string]*table
+}
+
+// New constructs an empty in-memory database.
+func New() *DB {
+	return &DB{
+		tables: make(map[string]*table),
+	}
+}
+
+// Update executes the function within a read-write transaction. Only one
+// read-write transaction runs at a time. When the function returns an error
+// its writes are undone, so none of them are kept.
+func (db *DB) Update(fn func(tx *Tx) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	tx := Tx{db: db, writable: true}
+	if err := fn(&tx); err != nil {
+		tx.rollback()
+		return err
+	}
+
+	return nil
+}
+
+// View executes the function within a read-only transaction.
+func (db *DB) View(fn func(tx *Tx) error) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return fn(&Tx{db: db})
+}
+
+// =============================================================================
+
+// Tx provides access to the tables while the database lock is held.
+type Tx struct {
+	db       *DB
+	writable bool
+	undo     []func()
+}
+
+// Insert stores the value in the table and returns the generated ID.
+func (tx *Tx) Insert(tableName string, value any, refs ...Ref) (int, error) {
+	if !tx.writable {
+		return 0, ErrReadOnly
+	}
+
+	if err := tx.checkRefs(refs); err != nil {
+		return 0, err
+	}
+
+	t := tx.table(tableName)
+	t.lastID++
+	t.rows[t.lastID] = row{value: value, refs: refs}
+
+	id := t.lastID
+	tx.undo = append(tx.undo, func() {
+		delete(t.rows, id)
+		t.lastID = id - 1
+	})
+
+	return id, nil
+}
+
+// Replace swaps the value stored under the specified ID. It reports false
+// when no such row exists.
+func (tx *Tx) Replace(tableName string, id int, value any, refs ...Ref) (bool, error) {
+	if !tx.writable {
+		return false, ErrReadOnly
+	}
+
+	t := tx.table(tableName)
+	prev, exists := t.rows[id]
+	if !exists {
+		return false, nil
+	}
+
+	if err := tx.checkRefs(refs); err != nil {
+		return false, err
+	}
+
+	t.rows[id] = row{value: value, refs: refs}
+	tx.undo = append(tx.undo, func() { t.rows[id] = prev })
+
+	return true, nil
+}
+
+// Delete removes the row with the specified ID. It reports false when no
+// such row exists.
+func (tx *Tx) Delete(tableName string, id int) (bool, error) {
+	if !tx.writable {
+		return false, ErrReadOnly
+	}
+
+	t := tx.table(tableName)
+	prev, exists := t.rows[id]
+	if !exists {
+		return false, nil
+	}
+
+	for name := range tx.db.tables {
+		if tx.Referenced(tableName, id, name) {
+			return false, fmt.Errorf("%s[%d] referenced by %s: %w", tableName, id, name, ErrForeignKey)
+		}
+	}
+
+	delete(t.rows, id)
+	tx.undo = append(tx.undo, func() { t.rows[id] = prev })
+
+	return true, nil
+}
+
+// Get returns the value stored under the specified ID.
+func (tx *Tx) Get(tableName string, id int) (any, bool) {
+	r, exists := tx.table(tableName).rows[id]
+	return r.value, exists
+}
+
+// Rows returns every row in the table ordered by ID.
+func (tx *Tx) Rows(tableName string) []Row {
+	t := tx.table(tableName)
+
+	rows := make([]Row, 0, len(t.rows))
+	for id, r := range t.rows {
+		rows = append(rows, Row{ID: id, Value: r.value})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].ID < rows[j].ID
+	})
+
+	return rows
+}
+
+// Referenced reports whether any row in the byTable table points at the row
+// with the specified ID.
+func (tx *Tx) Referenced(tableName string, id int, byTable string) bool {
+	for _, r := range tx.table(byTable).rows {
+		for _, ref := range r.refs {
+			if ref.Table == tableName && ref.ID == id {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (tx *Tx) checkRefs(refs []Ref) error {
+	for _, ref := range refs {
+		if _, exists := tx.table(ref.Table).rows[ref.ID]; !exists {
+			return fmt.Errorf("%s[%d] does not exist: %w", ref.Table, ref.ID, ErrForeignKey)
+		}
+	}
+
+	return nil
+}
+
+// rollback undoes the writes of the transaction, the last one first.
+func (tx *Tx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = nil
+}
+
+func (tx *Tx) table(name string) *table {
+	t, exists := tx.db.tables[name]
+	if !exists {
+		t = &table{rows: make(map[int]row)}
+
+		// Read-only transactions hand out an unregistered empty table so
+		// they never mutate the map while holding only the read lock.
+		if tx.writable {
+			tx.db.tables[name] = t
+		}
+	}
+
+	return t
+}
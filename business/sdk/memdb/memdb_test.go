@@ -0,0 +1,127 @@
+package memdb_test
+
+import (
+	"sync"
+	"testing"
+
+	"TODO-list/business/sdk/memdb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertAndRows(t *testing.T) {
+	db := memdb.New()
+
+	err := db.Update(func(tx *memdb.Tx) error {
+		for _, v := range []string{"a", "b", "c"} {
+			if _, err := tx.Insert("letters", v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	db.View(func(tx *memdb.Tx) error {
+		rows := tx.Rows("letters")
+		assert.Len(t, rows, 3)
+		assert.Equal(t, memdb.Row{ID: 1, Value: "a"}, rows[0])
+		assert.Equal(t, memdb.Row{ID: 3, Value: "c"}, rows[2])
+
+		_, err := tx.Insert("letters", "d")
+		assert.ErrorIs(t, err, memdb.ErrReadOnly)
+		return nil
+	})
+}
+
+func TestForeignKeys(t *testing.T) {
+	db := memdb.New()
+
+	db.Update(func(tx *memdb.Tx) error {
+		_, err := tx.Insert("child", "orphan", memdb.Ref{Table: "parent", ID: 1})
+		assert.ErrorIs(t, err, memdb.ErrForeignKey)
+
+		parentID, err := tx.Insert("parent", "parent")
+		assert.NoError(t, err)
+
+		childID, err := tx.Insert("child", "child", memdb.Ref{Table: "parent", ID: parentID})
+		assert.NoError(t, err)
+		assert.True(t, tx.Referenced("parent", parentID, "child"))
+
+		_, err = tx.Delete("parent", parentID)
+		assert.ErrorIs(t, err, memdb.ErrForeignKey)
+
+		deleted, err := tx.Delete("child", childID)
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = tx.Delete("parent", parentID)
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+		return nil
+	})
+}
+
+func TestConcurrentInserts(t *testing.T) {
+	db := memdb.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db.Update(func(tx *memdb.Tx) error {
+				_, err := tx.Insert("counter", struct{}{})
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+
+	db.View(func(tx *memdb.Tx) error {
+		assert.Len(t, tx.Rows("counter"), 50)
+		return nil
+	})
+}
+
+func TestUpdateRollback(t *testing.T) {
+	db := memdb.New()
+
+	db.Update(func(tx *memdb.Tx) error {
+		_, err := tx.Insert("letters", "a")
+		assert.NoError(t, err)
+		_, err = tx.Insert("letters", "b")
+		return err
+	})
+
+	err := db.Update(func(tx *memdb.Tx) error {
+		if _, err := tx.Insert("letters", "c"); err != nil {
+			return err
+		}
+		if _, err := tx.Replace("letters", 1, "A"); err != nil {
+			return err
+		}
+		if _, err := tx.Delete("letters", 2); err != nil {
+			return err
+		}
+		if _, err := tx.Insert("numbers", 1); err != nil {
+			return err
+		}
+		_, err := tx.Insert("letters", "d", memdb.Ref{Table: "missing", ID: 1})
+		return err
+	})
+	assert.ErrorIs(t, err, memdb.ErrForeignKey)
+
+	db.View(func(tx *memdb.Tx) error {
+		assert.Equal(t, []memdb.Row{{ID: 1, Value: "a"}, {ID: 2, Value: "b"}}, tx.Rows("letters"))
+		assert.Empty(t, tx.Rows("numbers"))
+		return nil
+	})
+
+	db.Update(func(tx *memdb.Tx) error {
+		id, err := tx.Insert("letters", "c")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+		return nil
+	})
+}
@@ -0,0 +1,313 @@
+// Package migrate applies the versioned database schema embedded in the
+// binary. Every dialect has its own ordered set of NNNN_name.up.sql and
+// NNNN_name.down.sql files and the applied versions are recorded in the
+// schema_version table.
+//
+// Scripts are split into statements on every semicolon, so a migration file
+// can not hold a semicolon anywhere else, such as in a string literal, a
+// comment or a trigger body.
+package migrate
+
+import (
+	"TODO-list/business/sdk/sqldb"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql
+var files embed.FS
+
+// ErrPending is returned by Check when migrations have not been applied.
+var ErrPending = errors.New("pending migrations")
+
+// Migration represents a single versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status represents a migration along with when it was applied.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt sql.NullTime
+}
+
+// Migrator manages the schema of a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    sqldb.Dialect
+	migrations []Migration
+}
+
+// New constructs a Migrator for the database using the migrations embedded
+// for the dialect.
+func New(db *sql.DB, dialect sqldb.Dialect) (*Migrator, error) {
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromMigrations(db, dialect, migrations), nil
+}
+
+// NewFromMigrations constructs a Migrator for the database using the
+// migrations provided, which must be ordered by version.
+func NewFromMigrations(db *sql.DB, dialect sqldb.Dialect, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}
+}
+
+// Load reads the migrations embedded for the dialect ordered by version.
+func Load(dialect sqldb.Dialect) ([]Migration, error) {
+	dir := path.Join("sql", string(dialect))
+
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		prefix, label, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", entry.Name(), err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+
+		switch direction {
+		case "up":
+			m.Up = string(data)
+		case "down":
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, mig := range pending {
+		err := m.withinTran(ctx, func(db sqldb.ExecQueryer) error {
+			if err := exec(ctx, db, mig.Up); err != nil {
+				return fmt.Errorf("up: %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			query := "INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)"
+			if _, err := db.ExecContext(ctx, query, mig.Version, mig.Name, time.Now()); err != nil {
+				return fmt.Errorf("record: %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return pending[:i], err
+		}
+	}
+
+	return pending, nil
+}
+
+// Down reverts the most recently applied migration. It reports false when
+// there was nothing to revert.
+func (m *Migrator) Down(ctx context.Context) (Migration, bool, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return Migration{}, false, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if _, exists := applied[mig.Version]; !exists {
+			continue
+		}
+
+		if mig.Down == "" {
+			return Migration{}, false, fmt.Errorf("down: %04d_%s is irreversible", mig.Version, mig.Name)
+		}
+
+		err := m.withinTran(ctx, func(db sqldb.ExecQueryer) error {
+			if err := exec(ctx, db, mig.Down); err != nil {
+				return fmt.Errorf("down: %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			query := "DELETE FROM schema_version WHERE version = ?"
+			if _, err := db.ExecContext(ctx, query, mig.Version); err != nil {
+				return fmt.Errorf("unrecord: %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return Migration{}, false, err
+		}
+
+		return mig, true, nil
+	}
+
+	return Migration{}, false, nil
+}
+
+// Status reports every known migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			AppliedAt: applied[mig.Version],
+		}
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, exists := applied[mig.Version]; !exists {
+			pending = append(pending, mig)
+		}
+	}
+
+	return pending, nil
+}
+
+// Check returns ErrPending when the database schema is not up to date.
+func (m *Migrator) Check(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%d to apply, next %04d_%s: %w", len(pending), pending[0].Version, pending[0].Name, ErrPending)
+	}
+
+	return nil
+}
+
+// applied returns the applied versions and when they were applied, creating
+// the schema_version table when it does not exist yet.
+func (m *Migrator) applied(ctx context.Context) (map[int]sql.NullTime, error) {
+	query := `CREATE TABLE IF NOT EXISTS schema_version (
+    version INT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at DATETIME NOT NULL
+)`
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("creating schema_version: %w", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_version")
+	if err != nil {
+		return nil, fmt.Errorf("querying schema_version: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]sql.NullTime)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = sql.NullTime{Time: appliedAt, Valid: true}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// withinTran runs fn with a transaction on SQLite, so a migration and its
+// schema_version record are applied together or not at all. MySQL commits
+// schema changes implicitly, so there fn runs statement by statement and a
+// failed migration can leave its earlier statements applied.
+func (m *Migrator) withinTran(ctx context.Context, fn func(db sqldb.ExecQueryer) error) error {
+	if m.dialect == sqldb.MySQL {
+		return fn(m.db)
+	}
+
+	return sqldb.WithinTran(ctx, m.db, func(tx *sql.Tx) error {
+		return fn(tx)
+	})
+}
+
+// exec runs each statement of the script in turn since the MySQL driver does
+// not accept multiple statements in a single call by default. Statements are
+// split on every semicolon, see the package documentation.
+func exec(ctx context.Context, db sqldb.ExecQueryer, script string) error {
+	for _, stmt := range strings.Split(script, ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
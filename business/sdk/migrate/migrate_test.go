@@ -0,0 +1,105 @@
+package migrate_test
+
+import (
+	"context"
+	"testing"
+
+	"TODO-list/business/sdk/migrate"
+	"TODO-list/business/sdk/sqldb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	for _, dialect := range []sqldb.Dialect{sqldb.MySQL, sqldb.SQLite} {
+		migrations, err := migrate.Load(dialect)
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+
+		for i, m := range migrations {
+			assert.Equal(t, i+1, m.Version, "%s versions must be contiguous", dialect)
+			assert.NotEmpty(t, m.Up)
+			assert.NotEmpty(t, m.Down)
+		}
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqldb.Open(sqldb.Config{Dialect: sqldb.SQLite, DSN: "file::memory:"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrate.New(db, sqldb.SQLite)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, migrator.Check(ctx), migrate.ErrPending)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, applied)
+	assert.NoError(t, migrator.Check(ctx))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.True(t, s.AppliedAt.Valid, "%04d_%s", s.Version, s.Name)
+	}
+
+	last := statuses[len(statuses)-1]
+	reverted, ok, err := migrator.Down(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, last.Version, reverted.Version)
+
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, last.Version, pending[0].Version)
+
+	for {
+		_, ok, err := migrator.Down(ctx)
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+	}
+
+	pending, err = migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, len(statuses))
+}
+
+func TestUpFailsAtomically(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqldb.Open(sqldb.Config{Dialect: sqldb.SQLite, DSN: "file::memory:"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator := migrate.NewFromMigrations(db, sqldb.SQLite, []migrate.Migration{
+		{Version: 1, Name: "create_note", Up: "CREATE TABLE note (id INT NOT NULL PRIMARY KEY)"},
+		{Version: 2, Name: "broken", Up: "ALTER TABLE note ADD COLUMN body TEXT;\nINSERT INTO missing (id) VALUES (1);"},
+	})
+
+	applied, err := migrator.Up(ctx)
+	assert.Error(t, err)
+	if assert.Len(t, applied, 1) {
+		assert.Equal(t, 1, applied[0].Version)
+	}
+
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, 2, pending[0].Version)
+	}
+
+	_, err = db.ExecContext(ctx, "INSERT INTO note (id, body) VALUES (1, 'x')")
+	assert.Error(t, err, "the column added by the failed migration is rolled back")
+}
@@ -0,0 +1,8 @@
+CREATE TABLE project (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by INT NOT NULL,
+    CONSTRAINT fk_project_created_by FOREIGN KEY (created_by) REFERENCES users (id)
+);
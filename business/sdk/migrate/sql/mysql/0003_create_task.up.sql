@@ -0,0 +1,13 @@
+CREATE TABLE task (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    project_id INT NOT NULL,
+    created_at DATETIME NOT NULL,
+    finished_at DATETIME NULL,
+    created_by INT NOT NULL,
+    assigned_to INT NULL,
+    CONSTRAINT fk_task_project FOREIGN KEY (project_id) REFERENCES project (id),
+    CONSTRAINT fk_task_created_by FOREIGN KEY (created_by) REFERENCES users (id),
+    CONSTRAINT fk_task_assigned_to FOREIGN KEY (assigned_to) REFERENCES users (id)
+);
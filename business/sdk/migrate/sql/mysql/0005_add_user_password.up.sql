@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN password_hash VARBINARY(255) NULL AFTER email;
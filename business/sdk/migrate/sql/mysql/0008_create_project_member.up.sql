@@ -0,0 +1,12 @@
+CREATE TABLE project_member (
+    project_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (project_id, user_id),
+    CONSTRAINT fk_project_member_project FOREIGN KEY (project_id) REFERENCES project (id) ON DELETE CASCADE,
+    CONSTRAINT fk_project_member_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+INSERT INTO project_member (project_id, user_id, role, created_at)
+SELECT id, created_by, 'owner', created_at FROM project;
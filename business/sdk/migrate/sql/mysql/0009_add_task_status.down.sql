@@ -0,0 +1,2 @@
+DROP TABLE task_transition;
+ALTER TABLE task DROP COLUMN status;
@@ -0,0 +1,18 @@
+ALTER TABLE task ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'todo' AFTER assigned_to;
+
+UPDATE task SET status = 'done' WHERE finished_at IS NOT NULL;
+
+CREATE TABLE task_transition (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    changed_by INT NULL,
+    changed_at DATETIME NOT NULL,
+    INDEX idx_task_transition_task (task_id, changed_at),
+    CONSTRAINT fk_task_transition_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_transition_changed_by FOREIGN KEY (changed_by) REFERENCES users (id)
+);
+
+INSERT INTO task_transition (task_id, from_status, to_status, changed_by, changed_at)
+SELECT id, 'todo', 'done', NULL, finished_at FROM task WHERE finished_at IS NOT NULL;
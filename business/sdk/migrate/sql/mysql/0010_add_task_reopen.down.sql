@@ -0,0 +1,2 @@
+ALTER TABLE task_transition DROP COLUMN reason;
+ALTER TABLE task DROP COLUMN reopen_count;
@@ -0,0 +1,3 @@
+ALTER TABLE task ADD COLUMN reopen_count INT NOT NULL DEFAULT 0 AFTER status;
+
+ALTER TABLE task_transition ADD COLUMN reason VARCHAR(500) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+ALTER TABLE task ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'medium' AFTER reopen_count;
+
+ALTER TABLE task ADD COLUMN due_at DATETIME NULL AFTER priority;
+
+ALTER TABLE task ADD COLUMN late_seconds BIGINT NOT NULL DEFAULT 0 AFTER due_at;
+
+CREATE INDEX idx_task_due_at ON task (due_at);
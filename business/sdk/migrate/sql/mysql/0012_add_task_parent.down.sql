@@ -0,0 +1,5 @@
+ALTER TABLE task DROP FOREIGN KEY fk_task_parent;
+
+DROP INDEX idx_task_parent ON task;
+
+ALTER TABLE task DROP COLUMN parent_id;
@@ -0,0 +1,5 @@
+ALTER TABLE task ADD COLUMN parent_id INT NULL AFTER project_id;
+
+CREATE INDEX idx_task_parent ON task (parent_id);
+
+ALTER TABLE task ADD CONSTRAINT fk_task_parent FOREIGN KEY (parent_id) REFERENCES task (id);
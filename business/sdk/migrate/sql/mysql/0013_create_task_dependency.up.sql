@@ -0,0 +1,9 @@
+CREATE TABLE task_dependency (
+    task_id INT NOT NULL,
+    blocker_id INT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (task_id, blocker_id),
+    INDEX idx_task_dependency_blocker (blocker_id),
+    CONSTRAINT fk_task_dependency_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_dependency_blocker FOREIGN KEY (blocker_id) REFERENCES task (id) ON DELETE CASCADE
+);
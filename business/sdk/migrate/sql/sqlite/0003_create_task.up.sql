@@ -0,0 +1,10 @@
+CREATE TABLE task (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    created_at DATETIME NOT NULL,
+    finished_at DATETIME NULL,
+    created_by INTEGER NOT NULL REFERENCES users (id),
+    assigned_to INTEGER NULL REFERENCES users (id)
+);
@@ -0,0 +1 @@
+DROP INDEX idx_task_created_at_id;
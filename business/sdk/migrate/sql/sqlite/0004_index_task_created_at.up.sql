@@ -0,0 +1 @@
+CREATE INDEX idx_task_created_at_id ON task (created_at, id);
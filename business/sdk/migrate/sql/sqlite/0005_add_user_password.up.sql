@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN password_hash BLOB NULL;
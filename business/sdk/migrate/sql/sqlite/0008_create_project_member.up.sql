@@ -0,0 +1,10 @@
+CREATE TABLE project_member (
+    project_id INTEGER NOT NULL REFERENCES project (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    role VARCHAR(20) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (project_id, user_id)
+);
+
+INSERT INTO project_member (project_id, user_id, role, created_at)
+SELECT id, created_by, 'owner', created_at FROM project;
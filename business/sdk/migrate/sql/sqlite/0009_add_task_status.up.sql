@@ -0,0 +1,17 @@
+ALTER TABLE task ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'todo';
+
+UPDATE task SET status = 'done' WHERE finished_at IS NOT NULL;
+
+CREATE TABLE task_transition (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    changed_by INTEGER NULL REFERENCES users (id),
+    changed_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_task_transition_task ON task_transition (task_id, changed_at);
+
+INSERT INTO task_transition (task_id, from_status, to_status, changed_by, changed_at)
+SELECT id, 'todo', 'done', NULL, finished_at FROM task WHERE finished_at IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX idx_task_due_at;
+
+ALTER TABLE task DROP COLUMN late_seconds;
+
+ALTER TABLE task DROP COLUMN due_at;
+
+ALTER TABLE task DROP COLUMN priority;
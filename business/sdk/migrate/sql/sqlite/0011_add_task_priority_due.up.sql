@@ -0,0 +1,7 @@
+ALTER TABLE task ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'medium';
+
+ALTER TABLE task ADD COLUMN due_at DATETIME NULL;
+
+ALTER TABLE task ADD COLUMN late_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_task_due_at ON task (due_at);
@@ -0,0 +1,3 @@
+DROP INDEX idx_task_parent;
+
+ALTER TABLE task DROP COLUMN parent_id;
@@ -0,0 +1,3 @@
+ALTER TABLE task ADD COLUMN parent_id INTEGER NULL REFERENCES task (id);
+
+CREATE INDEX idx_task_parent ON task (parent_id);
@@ -0,0 +1,8 @@
+CREATE TABLE task_dependency (
+    task_id INTEGER NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    blocker_id INTEGER NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (task_id, blocker_id)
+);
+
+CREATE INDEX idx_task_dependency_blocker ON task_dependency (blocker_id);
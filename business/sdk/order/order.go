@@ -0,0 +1,66 @@
+// Package order provides support for describing the ordering of data.
+package order
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Set of directions for data ordering.
+const (
+	ASC  = "ASC"
+	DESC = "DESC"
+)
+
+var directions = map[string]string{
+	"asc":  ASC,
+	"desc": DESC,
+}
+
+// By represents a field used to order by and direction.
+type By struct {
+	Field     string
+	Direction string
+}
+
+// NewBy constructs a new By value with no checks.
+func NewBy(field string, direction string) By {
+	return By{
+		Field:     field,
+		Direction: direction,
+	}
+}
+
+// Parse constructs the list of By values from a comma separated string in
+// the form "field[:direction],..." such as "project_id,created_at:desc". The
+// field names are translated through fieldMappings, so only the fields a
+// caller knows about can be used. The direction defaults to ASC and an empty
+// string returns the default order.
+func Parse(fieldMappings map[string]string, orderBy string, defaultOrder ...By) ([]By, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return defaultOrder, nil
+	}
+
+	var list []By
+	for _, part := range strings.Split(orderBy, ",") {
+		name, dir, _ := strings.Cut(strings.TrimSpace(part), ":")
+
+		field, exists := fieldMappings[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown order field %q", name)
+		}
+
+		direction := ASC
+		if dir != "" {
+			var exists bool
+			direction, exists = directions[strings.ToLower(dir)]
+			if !exists {
+				return nil, fmt.Errorf("unknown direction %q", dir)
+			}
+		}
+
+		list = append(list, NewBy(field, direction))
+	}
+
+	return list, nil
+}
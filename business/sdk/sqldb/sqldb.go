@@ -0,0 +1,308 @@
+// Package sqldb provides support for opening the SQL databases the stores
+// can run against.
+package sqldb
+
+import (
+	"TODO-list/foundation/metrics"
+	"TODO-list/foundation/otel"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrDBNotFound        = sql.ErrNoRows
+	ErrDBDuplicatedEntry = errors.New("duplicated entry")
+)
+
+var queryDuration = metrics.NewHistogram("db_query_duration_seconds",
+	"Latency of the database calls by operation.", metrics.DefBuckets, "operation")
+
+// Dialect identifies the SQL database engine behind a connection.
+type Dialect string
+
+// Set of supported dialects.
+const (
+	MySQL  Dialect = "mysql"
+	SQLite Dialect = "sqlite"
+)
+
+// ParseDialect converts a string to a dialect.
+func ParseDialect(value string) (Dialect, error) {
+	switch d := Dialect(value); d {
+	case MySQL, SQLite:
+		return d, nil
+	}
+
+	return "", fmt.Errorf("unknown dialect %q", value)
+}
+
+// DefaultDSN returns the data source name used for local development.
+func (d Dialect) DefaultDSN() string {
+	switch d {
+	case SQLite:
+		return "file:todolist.db"
+	default:
+		return "root:root@tcp(localhost:3306)/todolist?parseTime=true"
+	}
+}
+
+// Config is the required properties to use the database. Zero pool settings
+// keep the database/sql defaults.
+type Config struct {
+	Dialect         Dialect
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// Open knows how to open a database connection based on the configuration.
+//
+// The stores only issue portable SQL, so the differences between the engines
+// are settled here:
+//   - MySQL needs parseTime=true so DATETIME columns scan into time.Time,
+//     and clientFoundRows=true so RowsAffected counts the matched rows even
+//     when an UPDATE leaves their values unchanged.
+//   - SQLite stores booleans as 0/1 and DATETIME columns as text, which the
+//     driver converts back for columns declared DATETIME. The text is written
+//     with _time_format=sqlite because the default time.String layout carries
+//     the monotonic clock reading, so it never compares equal to a time read
+//     back and breaks the range and keyset conditions.
+//     Foreign keys are off by default and every connection to ":memory:" is
+//     a new database, so the pragma is set and the pool is always limited to
+//     a single connection.
+//     INTEGER PRIMARY KEY columns alias the rowid, so LastInsertId reports
+//     the generated ID the same way AUTO_INCREMENT does.
+func Open(cfg Config) (*sql.DB, error) {
+	dsn := cfg.DSN
+	if dsn == "" {
+		dsn = cfg.Dialect.DefaultDSN()
+	}
+
+	switch cfg.Dialect {
+	case MySQL:
+		if !strings.Contains(dsn, "parseTime=true") {
+			dsn = addParam(dsn, "parseTime=true")
+		}
+		if !strings.Contains(dsn, "clientFoundRows=") {
+			dsn = addParam(dsn, "clientFoundRows=true")
+		}
+
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, err
+		}
+		setPool(db, cfg)
+
+		return db, nil
+
+	case SQLite:
+		dsn = addParam(dsn, "_pragma=foreign_keys(1)")
+		dsn = addParam(dsn, "_pragma=busy_timeout(5000)")
+		if !strings.Contains(dsn, "_time_format=") {
+			dsn = addParam(dsn, "_time_format=sqlite")
+		}
+
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, err
+		}
+		setPool(db, cfg)
+		db.SetMaxOpenConns(1)
+
+		return db, nil
+	}
+
+	return nil, fmt.Errorf("unknown dialect %q", cfg.Dialect)
+}
+
+// StatusCheck returns nil if it can successfully talk to the database. The
+// ping is retried with a growing delay until the context is done, so callers
+// bound the wait with a deadline.
+func StatusCheck(ctx context.Context, db *sql.DB) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second)
+		defer cancel()
+	}
+
+	var pingError error
+	for attempts := 1; ; attempts++ {
+		pingError = db.PingContext(ctx)
+		if pingError == nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(pingError, ctx.Err())
+		case <-time.After(time.Duration(attempts) * 100 * time.Millisecond):
+		}
+	}
+
+	// Run a simple query to determine connectivity. Running this query forces
+	// a round trip through the database.
+	var tmp bool
+	return db.QueryRowContext(ctx, "SELECT true").Scan(&tmp)
+}
+
+// likeEscaper escapes the LIKE wildcards with '!'. A backslash would do in
+// MySQL but SQLite reads it as is in string literals, where MySQL needs two.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// EscapeLike escapes the value so a LIKE pattern matches it literally. The
+// condition has to declare the escape character with ESCAPE '!'.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// ExecQueryer is the part of *sql.DB and *sql.Tx the helpers below use, so
+// the same statements run inside and outside a transaction.
+type ExecQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithinTran runs fn inside a transaction, committing when fn succeeds and
+// rolling back when it fails. With SQLite the pool holds one connection, so
+// fn must run every statement through tx.
+func WithinTran(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tran: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tran: %w", err)
+	}
+
+	return nil
+}
+
+// ExecContext executes a statement that inserts, updates or deletes rows.
+// Unique constraint violations reported by either driver are returned as
+// ErrDBDuplicatedEntry.
+func ExecContext(ctx context.Context, db ExecQueryer, query string, args ...any) (sql.Result, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.exec", attribute.String("db.statement", query))
+	defer span.End()
+	defer observe("exec", time.Now())
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if isDuplicate(err) {
+			return nil, fmt.Errorf("%w: %w", ErrDBDuplicatedEntry, err)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// QueryContext executes a query that returns rows. The span covers the query
+// itself and not the iteration over the rows.
+func QueryContext(ctx context.Context, db ExecQueryer, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.query", attribute.String("db.statement", query))
+	defer span.End()
+	defer observe("query", time.Now())
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// QueryRowContext executes a query that is expected to return at most one
+// row.
+func QueryRowContext(ctx context.Context, db ExecQueryer, query string, args ...any) *sql.Row {
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.queryrow", attribute.String("db.statement", query))
+	defer span.End()
+	defer observe("queryrow", time.Now())
+
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// ExportStats publishes the connection pool statistics of the database as
+// metrics. It must only be called once.
+func ExportStats(db *sql.DB) {
+	gauges := []struct {
+		name  string
+		help  string
+		value func(sql.DBStats) float64
+	}{
+		{"db_max_open_connections", "Maximum number of open connections to the database.", func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }},
+		{"db_open_connections", "Number of established connections both in use and idle.", func(s sql.DBStats) float64 { return float64(s.OpenConnections) }},
+		{"db_in_use_connections", "Number of connections currently in use.", func(s sql.DBStats) float64 { return float64(s.InUse) }},
+		{"db_idle_connections", "Number of idle connections.", func(s sql.DBStats) float64 { return float64(s.Idle) }},
+	}
+	for _, g := range gauges {
+		value := g.value
+		metrics.NewGaugeFunc(g.name, g.help, func() float64 { return value(db.Stats()) })
+	}
+
+	metrics.NewCounterFunc("db_wait_count_total", "Total number of connections waited for.",
+		func() float64 { return float64(db.Stats().WaitCount) })
+	metrics.NewCounterFunc("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		func() float64 { return db.Stats().WaitDuration.Seconds() })
+}
+
+func observe(operation string, start time.Time) {
+	queryDuration.Observe(time.Since(start).Seconds(), operation)
+}
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		const duplicateEntry = 1062
+		return mysqlErr.Number == duplicateEntry
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return true
+		}
+	}
+
+	return false
+}
+
+func setPool(db *sql.DB, cfg Config) {
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+}
+
+func addParam(dsn string, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+
+	return dsn + "?" + param
+}
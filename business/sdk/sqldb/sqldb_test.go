@@ -0,0 +1,170 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"TODO-list/business/domain/projectbus"
+	"TODO-list/business/domain/projectbus/stores/projectdb"
+	"TODO-list/business/domain/taskbus"
+	"TODO-list/business/domain/taskbus/stores/taskdb"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/domain/userbus/stores/userdb"
+	"TODO-list/business/sdk/migrate"
+	"TODO-list/business/sdk/order"
+	"TODO-list/business/sdk/page"
+	"TODO-list/business/sdk/sqldb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStores(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqldb.Open(sqldb.Config{Dialect: sqldb.SQLite, DSN: "file::memory:"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrate.New(db, sqldb.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	userBus := userbus.NewBusiness(userdb.NewStore(db))
+	projectBus := projectbus.NewBusiness(projectdb.NewStore(db), userBus)
+	taskBus := taskbus.NewBusiness(taskdb.NewStore(db), userBus, projectBus)
+
+	usr, err := userBus.Create(ctx, userbus.NewUser{Name: "User", Email: "user@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, usr.ID)
+
+	prj, err := projectBus.Create(ctx, projectbus.NewProject{Name: "Project", CreatedBy: usr.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, prj.ID)
+
+	task, err := taskBus.Create(ctx, taskbus.NewTask{
+		Title:      "Task",
+		ProjectID:  prj.ID,
+		CreatedBy:  usr.ID,
+		AssignedTo: sql.NullInt32{Int32: int32(usr.ID), Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.ID)
+
+	require.NoError(t, taskBus.Finish(ctx, task.ID, sql.NullInt32{Int32: int32(usr.ID), Valid: true}))
+
+	next, err := taskBus.Create(ctx, taskbus.NewTask{Title: "Next", ProjectID: prj.ID, CreatedBy: usr.ID})
+	require.NoError(t, err)
+
+	var walked []int
+	var after *taskbus.Cursor
+	for {
+		tasks, err := taskBus.QueryAfter(ctx, taskbus.QueryFilter{}, after, 1)
+		require.NoError(t, err)
+		if len(tasks) == 0 {
+			break
+		}
+		walked = append(walked, tasks[0].ID)
+		cursor := taskbus.CursorOf(tasks[0])
+		after = &cursor
+	}
+	assert.Equal(t, []int{task.ID, next.ID}, walked)
+	require.NoError(t, taskBus.Delete(ctx, next.ID))
+
+	task, err = taskBus.QueryByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.True(t, task.FinishedAt.Valid)
+	assert.WithinDuration(t, time.Now(), task.FinishedAt.Time, time.Minute)
+	assert.WithinDuration(t, time.Now(), task.CreatedAt, time.Minute)
+	assert.Equal(t, int32(usr.ID), task.AssignedTo.Int32)
+	assert.Equal(t, taskbus.StatusDone, task.Status)
+
+	// A bound in another offset still matches, as SQLite compares the dates
+	// as text.
+	end := task.FinishedAt.Time.In(time.FixedZone("BRT", -3*60*60))
+	count, err := taskBus.Count(ctx, taskbus.QueryFilter{EndCreatedDate: &end, EndFinishedDate: &end})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	trs, err := taskBus.QueryTransitions(ctx, task.ID)
+	require.NoError(t, err)
+	if assert.Len(t, trs, 1) {
+		assert.Equal(t, taskbus.StatusTodo, trs[0].From)
+		assert.Equal(t, int32(usr.ID), trs[0].ChangedBy.Int32)
+	}
+
+	_, err = taskBus.Transition(ctx, task.ID, taskbus.StatusInProgress, sql.NullInt32{})
+	assert.ErrorIs(t, err, taskbus.ErrInvalidTransition)
+
+	require.NoError(t, projectBus.Delete(ctx, prj.ID))
+
+	prj, err = projectBus.QueryById(ctx, prj.ID)
+	require.NoError(t, err)
+	assert.False(t, prj.Active)
+
+	require.NoError(t, userBus.Deactivate(ctx, usr.ID))
+
+	inactive := false
+	domain := "example.com"
+	filter := userbus.QueryFilter{Active: &inactive, EmailDomain: &domain}
+	users, err := userBus.Query(ctx, filter, []order.By{order.NewBy(userbus.OrderByName, order.DESC)}, page.MustParse("1", "10"))
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.False(t, users[0].Active)
+	assert.True(t, users[0].CreatedAt.Valid)
+
+	// The name filters match the value literally, wildcards included.
+	for name, want := range map[string]int{"Us": 1, "U_er": 0, "%ser": 0} {
+		count, err = userBus.Count(ctx, userbus.QueryFilter{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, want, count, name)
+	}
+
+	title := "T_sk"
+	count, err = taskBus.Count(ctx, taskbus.QueryFilter{Title: &title})
+	require.NoError(t, err)
+	assert.Zero(t, count, "task titles match literally too")
+
+	projectName := "r_j"
+	count, err = projectBus.Count(ctx, projectbus.QueryFilter{Name: &projectName})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	end = users[0].CreatedAt.Time.In(time.FixedZone("BRT", -3*60*60))
+	count, err = userBus.Count(ctx, userbus.QueryFilter{EndCreatedDate: &end})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "a bound in another offset still matches the user")
+
+	active := true
+	projects, err := projectBus.Query(ctx, projectbus.QueryFilter{Active: &active}, []order.By{projectbus.DefaultOrderBy}, page.MustParse("1", "10"))
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+
+	_, err = taskBus.Create(ctx, taskbus.NewTask{Title: "Task", ProjectID: prj.ID, CreatedBy: usr.ID})
+	assert.ErrorIs(t, err, projectbus.ErrProjectInactive)
+
+	_, err = userBus.Create(ctx, userbus.NewUser{Name: "Other", Email: "user@example.com"})
+	assert.ErrorIs(t, err, userbus.ErrUniqueEmail)
+
+	_, err = taskBus.QueryByID(ctx, 99)
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
+
+	err = taskBus.Delete(ctx, 99)
+	assert.ErrorIs(t, err, taskbus.ErrNotFound)
+}
+
+func TestStatusCheck(t *testing.T) {
+	db, err := sqldb.Open(sqldb.Config{Dialect: sqldb.SQLite, DSN: "file::memory:"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, sqldb.StatusCheck(ctx, db))
+
+	db.Close()
+	assert.Error(t, sqldb.StatusCheck(ctx, db))
+}
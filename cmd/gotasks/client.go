@@ -0,0 +1,380 @@
+package main
+
+import (
+	"TODO-list/app/sdk/errs"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// client calls the task service HTTP API.
+type client struct {
+	baseURL      string
+	token        string
+	refreshToken string
+	http         *http.Client
+
+	// onRefresh is called with the new tokens after an expired access token
+	// was refreshed, so they can be saved.
+	onRefresh func(token string, refreshToken string) error
+}
+
+func newClient(baseURL string, token string, refreshToken string) *client {
+	return &client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		token:        token,
+		refreshToken: refreshToken,
+		http:         &http.Client{Timeout: 10 * time.Second},
+		onRefresh:    func(string, string) error { return nil },
+	}
+}
+
+// do sends the request and returns the raw response body. A request refused
+// for an expired access token is sent again after refreshing the token when
+// there is a refresh token.
+func (c *client) do(ctx context.Context, method string, path string, body any) ([]byte, error) {
+	data, err := c.send(ctx, method, path, body)
+
+	var errsErr *errs.Error
+	if !errors.As(err, &errsErr) || errsErr.Code != errs.Unauthenticated || c.refreshToken == "" || strings.HasPrefix(path, "/api/auth/") {
+		return data, err
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.send(ctx, method, path, body)
+}
+
+// refresh exchanges the refresh token for new tokens.
+func (c *client) refresh(ctx context.Context) error {
+	var tkn tokens
+	if _, err := c.call(ctx, http.MethodPost, "/api/auth/refresh", map[string]string{"refresh_token": c.refreshToken}, &tkn); err != nil {
+		return err
+	}
+
+	c.token = tkn.AccessToken
+	c.refreshToken = tkn.RefreshToken
+
+	if err := c.onRefresh(c.token, c.refreshToken); err != nil {
+		return errs.Newf(errs.Internal, "saving tokens: %s", err)
+	}
+
+	return nil
+}
+
+// send sends the request and returns the raw response body. Failed responses
+// are returned as *errs.Error so the caller can use the error code.
+func (c *client) send(ctx context.Context, method string, path string, body any) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errs.Newf(errs.Internal, "encoding request: %s", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return nil, errs.Newf(errs.InvalidArgument, "creating request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errs.Newf(errs.Unavailable, "calling %s: %s", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errs.Newf(errs.Unavailable, "reading response: %s", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errResp errs.Error
+		if err := json.Unmarshal(data, &errResp); err != nil || errResp.Message == "" {
+			return nil, errs.Newf(errs.Unknown, "%s %s: %s", method, path, resp.Status)
+		}
+		return nil, &errResp
+	}
+
+	return data, nil
+}
+
+// call sends the request and decodes the response body into v when v is
+// not nil.
+func (c *client) call(ctx context.Context, method string, path string, body any, v any) ([]byte, error) {
+	data, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if v != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, errs.Newf(errs.Unknown, "decoding response: %s", err)
+		}
+	}
+
+	return data, nil
+}
+
+// =============================================================================
+
+// task mirrors the task document returned by the API.
+type task struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   int        `json:"project_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  time.Time  `json:"finished_at"`
+	CreatedBy   int        `json:"created_by"`
+	AssignedTo  int        `json:"assigned_to"`
+	Status      string     `json:"status"`
+	ReopenCount int        `json:"reopen_count"`
+	Priority    string     `json:"priority"`
+	DueAt       *time.Time `json:"due_at"`
+	LateSeconds int64      `json:"late_seconds"`
+	ParentID    int        `json:"parent_id"`
+	Progress    *progress  `json:"progress"`
+}
+
+// progress mirrors the summary of the subtasks of a task.
+type progress struct {
+	Done    int `json:"done"`
+	Total   int `json:"total"`
+	Percent int `json:"percent"`
+}
+
+// transition mirrors a change of status returned by the API.
+type transition struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedBy int       `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+	Reason    string    `json:"reason"`
+}
+
+// dependency mirrors the link between a task and its blocker.
+type dependency struct {
+	TaskID    int       `json:"task_id"`
+	BlockerID int       `json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// result mirrors the envelope of the paged listings.
+type result[T any] struct {
+	Items       []T `json:"items"`
+	Total       int `json:"total"`
+	Page        int `json:"page"`
+	RowsPerPage int `json:"rowsPerPage"`
+}
+
+// project mirrors the project document returned by the API.
+type project struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy int       `json:"created_by"`
+}
+
+// member mirrors the project member document returned by the API.
+type member struct {
+	ProjectID int       `json:"project_id"`
+	UserID    int       `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// user mirrors the user document returned by the API.
+type user struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// tokens mirrors the tokens returned on login and refresh.
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (c *client) login(ctx context.Context, email string, password string) (tokens, []byte, error) {
+	var tkn tokens
+	data, err := c.call(ctx, http.MethodPost, "/api/auth/token", map[string]string{"email": email, "password": password}, &tkn)
+	return tkn, data, err
+}
+
+func (c *client) revoke(ctx context.Context, refreshToken string) error {
+	_, err := c.call(ctx, http.MethodPost, "/api/auth/revoke", map[string]string{"refresh_token": refreshToken}, nil)
+	return err
+}
+
+func (c *client) queryTasks(ctx context.Context, params url.Values) (result[task], []byte, error) {
+	return c.queryTaskList(ctx, "/api/tasks", params)
+}
+
+func (c *client) querySubtasks(ctx context.Context, id int, params url.Values) (result[task], []byte, error) {
+	return c.queryTaskList(ctx, fmt.Sprintf("/api/tasks/%d/subtasks", id), params)
+}
+
+func (c *client) queryTaskList(ctx context.Context, path string, params url.Values) (result[task], []byte, error) {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var tasks result[task]
+	data, err := c.call(ctx, http.MethodGet, path, nil, &tasks)
+	return tasks, data, err
+}
+
+func (c *client) queryTask(ctx context.Context, id int) (task, []byte, error) {
+	var t task
+	data, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/tasks/%d", id), nil, &t)
+	return t, data, err
+}
+
+func (c *client) createTask(ctx context.Context, nt any) (task, []byte, error) {
+	var t task
+	data, err := c.call(ctx, http.MethodPost, "/api/tasks", nt, &t)
+	return t, data, err
+}
+
+func (c *client) updateTask(ctx context.Context, id int, ut any) error {
+	_, err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/tasks/%d", id), ut, nil)
+	return err
+}
+
+func (c *client) finishTask(ctx context.Context, id int, subtasks bool) error {
+	path := fmt.Sprintf("/api/tasks/finish/%d", id)
+	if subtasks {
+		path += "?subtasks=true"
+	}
+
+	_, err := c.call(ctx, http.MethodPut, path, nil, nil)
+	return err
+}
+
+func (c *client) transitionTask(ctx context.Context, id int, status string) (task, []byte, error) {
+	body := struct {
+		Status string `json:"status"`
+	}{
+		Status: status,
+	}
+
+	var t task
+	data, err := c.call(ctx, http.MethodPost, fmt.Sprintf("/api/tasks/%d/transition", id), body, &t)
+	return t, data, err
+}
+
+func (c *client) reopenTask(ctx context.Context, id int, reason string) (task, []byte, error) {
+	body := struct {
+		Reason string `json:"reason"`
+	}{
+		Reason: reason,
+	}
+
+	var t task
+	data, err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/tasks/reopen/%d", id), body, &t)
+	return t, data, err
+}
+
+func (c *client) queryTransitions(ctx context.Context, id int) ([]transition, []byte, error) {
+	var trs []transition
+	data, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/tasks/%d/transitions", id), nil, &trs)
+	return trs, data, err
+}
+
+func (c *client) queryBlockers(ctx context.Context, id int) ([]task, []byte, error) {
+	var tasks []task
+	data, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/tasks/%d/dependencies", id), nil, &tasks)
+	return tasks, data, err
+}
+
+func (c *client) addDependency(ctx context.Context, id int, blockerID int) (dependency, []byte, error) {
+	body := struct {
+		BlockerID int `json:"blocker_id"`
+	}{
+		BlockerID: blockerID,
+	}
+
+	var dep dependency
+	data, err := c.call(ctx, http.MethodPost, fmt.Sprintf("/api/tasks/%d/dependencies", id), body, &dep)
+	return dep, data, err
+}
+
+func (c *client) removeDependency(ctx context.Context, id int, blockerID int) error {
+	_, err := c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/tasks/%d/dependencies/%d", id, blockerID), nil, nil)
+	return err
+}
+
+func (c *client) deleteTask(ctx context.Context, id int) error {
+	_, err := c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/tasks/%d", id), nil, nil)
+	return err
+}
+
+func (c *client) queryProjects(ctx context.Context) (result[project], []byte, error) {
+	var projects result[project]
+	data, err := c.call(ctx, http.MethodGet, "/api/project", nil, &projects)
+	return projects, data, err
+}
+
+func (c *client) createProject(ctx context.Context, np any) (project, []byte, error) {
+	var p project
+	data, err := c.call(ctx, http.MethodPost, "/api/project", np, &p)
+	return p, data, err
+}
+
+func (c *client) criticalPath(ctx context.Context, projectID int) ([]task, []byte, error) {
+	var tasks []task
+	data, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/project/%d/critical-path", projectID), nil, &tasks)
+	return tasks, data, err
+}
+
+func (c *client) queryMembers(ctx context.Context, projectID int) ([]member, []byte, error) {
+	var members []member
+	data, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/project/%d/members", projectID), nil, &members)
+	return members, data, err
+}
+
+func (c *client) addMember(ctx context.Context, projectID int, nm any) (member, []byte, error) {
+	var m member
+	data, err := c.call(ctx, http.MethodPost, fmt.Sprintf("/api/project/%d/members", projectID), nm, &m)
+	return m, data, err
+}
+
+func (c *client) removeMember(ctx context.Context, projectID int, userID int) error {
+	_, err := c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/project/%d/members/%d", projectID, userID), nil, nil)
+	return err
+}
+
+func (c *client) queryUsers(ctx context.Context) (result[user], []byte, error) {
+	var users result[user]
+	data, err := c.call(ctx, http.MethodGet, "/api/users", nil, &users)
+	return users, data, err
+}
+
+func (c *client) createUser(ctx context.Context, nu any) (user, []byte, error) {
+	var u user
+	data, err := c.call(ctx, http.MethodPost, "/api/users", nu, &u)
+	return u, data, err
+}
@@ -0,0 +1,853 @@
+package main
+
+import (
+	"TODO-list/app/sdk/errs"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// env carries what every command needs.
+type env struct {
+	out        io.Writer
+	output     string
+	client     *client
+	cfg        config
+	configPath string
+}
+
+// usageError reports a command invoked with the wrong arguments.
+func usageError(format string, v ...any) error {
+	return errs.Newf(errs.InvalidArgument, format, v...)
+}
+
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, usageError("invalid id %q", arg)
+	}
+	return id, nil
+}
+
+// printJSON writes the raw API response indented.
+func (e env) printJSON(data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return errs.Newf(errs.Unknown, "formatting response: %s", err)
+	}
+	buf.WriteByte('\n')
+
+	_, err := e.out.Write(buf.Bytes())
+	return err
+}
+
+func (e env) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func formatDue(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
+
+func formatProgress(p *progress) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// parseDue parses an RFC3339 due date, returning nil when it is empty.
+func parseDue(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, usageError("invalid due date %q, use RFC3339 such as 2026-11-02T18:00:00-03:00", value)
+	}
+
+	return &t, nil
+}
+
+func formatID(id int) string {
+	if id == 0 {
+		return "-"
+	}
+	return strconv.Itoa(id)
+}
+
+func formatSet(set bool) string {
+	if set {
+		return "set"
+	}
+	return "-"
+}
+
+// =============================================================================
+
+func listTasks(ctx context.Context, e env, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	projectID := fs.Int("project", 0, "only tasks of the project")
+	assignedTo := fs.Int("assigned", 0, "only tasks assigned to the user")
+	finished := fs.String("finished", "", "only finished (true) or open (false) tasks")
+	status := fs.String("status", "", "only tasks in the status")
+	priority := fs.String("priority", "", "only tasks with the priority")
+	due := fs.String("due", "", "only open tasks that are overdue, due today or due this week")
+	tz := fs.String("tz", os.Getenv("TZ"), "time zone of today and this week, such as America/Sao_Paulo")
+	title := fs.String("title", "", "only tasks whose title contains the text")
+	orderBy := fs.String("order", "", "fields to order by, such as created_at:desc")
+	page := fs.Int("page", 0, "page to show")
+	rows := fs.Int("rows", 0, "tasks per page")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return usageError("usage: gotasks list [-project <id>] [-assigned <user>] [-finished true|false] [-status <status>] [-priority <priority>] [-due overdue|today|week] [-tz <zone>] [-title <text>] [-order <fields>] [-page <n>] [-rows <n>]")
+	}
+
+	params := url.Values{}
+	setInt := func(key string, v int) {
+		if v != 0 {
+			params.Set(key, strconv.Itoa(v))
+		}
+	}
+	setString := func(key string, v string) {
+		if v != "" {
+			params.Set(key, v)
+		}
+	}
+	setInt("project_id", *projectID)
+	setInt("assigned_to", *assignedTo)
+	setString("finished", *finished)
+	setString("status", *status)
+	setString("priority", *priority)
+	setString("due", *due)
+	if *due != "" {
+		setString("tz", *tz)
+	}
+	setString("title", *title)
+	setString("orderBy", *orderBy)
+	setInt("page", *page)
+	setInt("rows", *rows)
+
+	tasks, data, err := e.client.queryTasks(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	return printTasks(e, tasks)
+}
+
+// printTasks writes a page of tasks as a table.
+func printTasks(e env, tasks result[task]) error {
+	tw := e.table()
+	fmt.Fprintln(tw, "ID\tTITLE\tPROJECT\tASSIGNED\tSTATUS\tPRIORITY\tDUE\tSUBTASKS\tCREATED\tFINISHED")
+	for _, t := range tasks.Items {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Title, t.ProjectID, formatID(t.AssignedTo), t.Status, t.Priority, formatDue(t.DueAt), formatProgress(t.Progress), formatTime(t.CreatedAt), formatTime(t.FinishedAt))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	printPage(e, len(tasks.Items), tasks.Total, tasks.Page, tasks.RowsPerPage, "tasks")
+	return nil
+}
+
+func listSubtasks(ctx context.Context, e env, args []string) error {
+	fs := flag.NewFlagSet("subtasks", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	page := fs.Int("page", 0, "page to show")
+	rows := fs.Int("rows", 0, "tasks per page")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return usageError("usage: gotasks subtasks [-page <n>] [-rows <n>] <id>")
+	}
+
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	if *page != 0 {
+		params.Set("page", strconv.Itoa(*page))
+	}
+	if *rows != 0 {
+		params.Set("rows", strconv.Itoa(*rows))
+	}
+
+	tasks, data, err := e.client.querySubtasks(ctx, id, params)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	return printTasks(e, tasks)
+}
+
+func showTask(ctx context.Context, e env, args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: gotasks show <id>")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	t, data, err := e.client.queryTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	tw := e.table()
+	fmt.Fprintf(tw, "ID:\t%d\n", t.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", t.Title)
+	fmt.Fprintf(tw, "Description:\t%s\n", t.Description)
+	fmt.Fprintf(tw, "Project:\t%d\n", t.ProjectID)
+	fmt.Fprintf(tw, "Created by:\t%d\n", t.CreatedBy)
+	fmt.Fprintf(tw, "Assigned to:\t%s\n", formatID(t.AssignedTo))
+	fmt.Fprintf(tw, "Parent:\t%s\n", formatID(t.ParentID))
+	fmt.Fprintf(tw, "Subtasks:\t%s\n", formatProgress(t.Progress))
+	fmt.Fprintf(tw, "Status:\t%s\n", t.Status)
+	fmt.Fprintf(tw, "Priority:\t%s\n", t.Priority)
+	fmt.Fprintf(tw, "Reopened:\t%d\n", t.ReopenCount)
+	fmt.Fprintf(tw, "Created at:\t%s\n", formatTime(t.CreatedAt))
+	fmt.Fprintf(tw, "Due at:\t%s\n", formatDue(t.DueAt))
+	fmt.Fprintf(tw, "Finished at:\t%s\n", formatTime(t.FinishedAt))
+	if t.LateSeconds > 0 {
+		fmt.Fprintf(tw, "Late by:\t%s\n", time.Duration(t.LateSeconds)*time.Second)
+	}
+	return tw.Flush()
+}
+
+func createTask(ctx context.Context, e env, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	projectID := fs.Int("project", 0, "project the task belongs to")
+	assignTo := fs.Int("assign", 0, "user the task is assigned to")
+	priority := fs.String("priority", "", "low, medium, high or urgent")
+	due := fs.String("due", "", "due date in RFC3339, such as 2026-11-02T18:00:00-03:00")
+	parentID := fs.Int("parent", 0, "task the new task is a subtask of")
+	if err := fs.Parse(args); err != nil || fs.NArg() < 1 || fs.NArg() > 2 || *projectID == 0 {
+		return usageError("usage: gotasks create -project <id> [-assign <user id>] [-parent <task id>] [-priority <priority>] [-due <time>] <title> [description]")
+	}
+
+	dueAt, err := parseDue(*due)
+	if err != nil {
+		return err
+	}
+
+	nt := struct {
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		ProjectID   int        `json:"project_id"`
+		AssignedTo  *int       `json:"assigned_to"`
+		Priority    string     `json:"priority,omitempty"`
+		DueAt       *time.Time `json:"due_at"`
+		ParentID    *int       `json:"parent_id"`
+	}{
+		Title:     fs.Arg(0),
+		ProjectID: *projectID,
+		Priority:  *priority,
+		DueAt:     dueAt,
+	}
+	if *parentID != 0 {
+		nt.ParentID = parentID
+	}
+	if fs.NArg() == 2 {
+		nt.Description = fs.Arg(1)
+	}
+	if *assignTo != 0 {
+		nt.AssignedTo = assignTo
+	}
+
+	t, data, err := e.client.createTask(ctx, nt)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	fmt.Fprintf(e.out, "task %d created\n", t.ID)
+	return nil
+}
+
+func updateTask(ctx context.Context, e env, args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	priority := fs.String("priority", "", "low, medium, high or urgent")
+	due := fs.String("due", "", "due date in RFC3339, or none to remove it")
+	parent := fs.String("parent", "", "task to move the task under, or none to make it top level")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 3 {
+		return usageError("usage: gotasks update [-priority <priority>] [-due <time>|none] [-parent <id>|none] <id> <title> <description>")
+	}
+	args = fs.Args()
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	// The API replaces every field, so keep the current assignee, due date
+	// and parent unless a new one is given.
+	t, _, err := e.client.queryTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ut := struct {
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		AssignedTo  *int       `json:"assigned_to"`
+		Priority    string     `json:"priority,omitempty"`
+		DueAt       *time.Time `json:"due_at"`
+		ParentID    *int       `json:"parent_id"`
+	}{
+		Title:       args[1],
+		Description: args[2],
+		Priority:    *priority,
+		DueAt:       t.DueAt,
+	}
+	if t.AssignedTo != 0 {
+		ut.AssignedTo = &t.AssignedTo
+	}
+	if t.ParentID != 0 {
+		ut.ParentID = &t.ParentID
+	}
+
+	switch *parent {
+	case "":
+	case "none":
+		ut.ParentID = nil
+	default:
+		parentID, err := parseID(*parent)
+		if err != nil {
+			return err
+		}
+		ut.ParentID = &parentID
+	}
+
+	switch *due {
+	case "":
+	case "none":
+		ut.DueAt = nil
+	default:
+		if ut.DueAt, err = parseDue(*due); err != nil {
+			return err
+		}
+	}
+
+	if err := e.client.updateTask(ctx, id, ut); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "task %d updated\n", id)
+	return nil
+}
+
+func finishTask(ctx context.Context, e env, args []string) error {
+	fs := flag.NewFlagSet("finish", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	subtasks := fs.Bool("subtasks", false, "finish the open subtasks too")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return usageError("usage: gotasks finish [-subtasks] <id>")
+	}
+
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err := e.client.finishTask(ctx, id, *subtasks); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "task %d finished\n", id)
+	return nil
+}
+
+func moveTask(ctx context.Context, e env, args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: gotasks move <id> <status>")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	t, data, err := e.client.transitionTask(ctx, id, args[1])
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	fmt.Fprintf(e.out, "task %d is %s\n", id, t.Status)
+	return nil
+}
+
+func reopenTask(ctx context.Context, e env, args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: gotasks reopen <id> <reason>")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	t, data, err := e.client.reopenTask(ctx, id, args[1])
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	fmt.Fprintf(e.out, "task %d reopened, %d time(s) so far\n", id, t.ReopenCount)
+	return nil
+}
+
+func taskHistory(ctx context.Context, e env, args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: gotasks history <id>")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	trs, data, err := e.client.queryTransitions(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	tw := e.table()
+	fmt.Fprintln(tw, "FROM\tTO\tBY\tAT\tREASON")
+	for _, tr := range trs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", tr.From, tr.To, formatID(tr.ChangedBy), formatTime(tr.ChangedAt), tr.Reason)
+	}
+	return tw.Flush()
+}
+
+func dependencies(ctx context.Context, e env, args []string) error {
+	const usage = "usage: gotasks deps <id> [list | add <blocker> | remove <blocker>]"
+
+	if len(args) == 0 {
+		return usageError(usage)
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	args = args[1:]
+
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "list"):
+		blockers, data, err := e.client.queryBlockers(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		tw := e.table()
+		fmt.Fprintln(tw, "BLOCKER\tTITLE\tPROJECT\tSTATUS")
+		for _, t := range blockers {
+			fmt.Fprintf(tw, "%d\t%s\t%d\t%s\n", t.ID, t.Title, t.ProjectID, t.Status)
+		}
+		return tw.Flush()
+
+	case len(args) == 2 && args[0] == "add":
+		blockerID, err := parseID(args[1])
+		if err != nil {
+			return err
+		}
+
+		_, data, err := e.client.addDependency(ctx, id, blockerID)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		fmt.Fprintf(e.out, "task %d blocked by task %d\n", id, blockerID)
+		return nil
+
+	case len(args) == 2 && args[0] == "remove":
+		blockerID, err := parseID(args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := e.client.removeDependency(ctx, id, blockerID); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.out, "task %d no longer blocked by task %d\n", id, blockerID)
+		return nil
+	}
+
+	return usageError(usage)
+}
+
+func deleteTask(ctx context.Context, e env, args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: gotasks delete <id>")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := e.client.deleteTask(ctx, id); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "task %d deleted\n", id)
+	return nil
+}
+
+// printPage tells which page was shown when the listing does not fit in one.
+func printPage(e env, shown int, total int, page int, rowsPerPage int, noun string) {
+	if shown >= total || rowsPerPage == 0 {
+		return
+	}
+
+	pages := (total + rowsPerPage - 1) / rowsPerPage
+	fmt.Fprintf(e.out, "page %d of %d, %d %s\n", page, pages, total, noun)
+}
+
+// =============================================================================
+
+func projects(ctx context.Context, e env, args []string) error {
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "list"):
+		projects, data, err := e.client.queryProjects(ctx)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		tw := e.table()
+		fmt.Fprintln(tw, "ID\tNAME\tACTIVE\tCREATED BY\tCREATED")
+		for _, p := range projects.Items {
+			fmt.Fprintf(tw, "%d\t%s\t%t\t%d\t%s\n", p.ID, p.Name, p.Active, p.CreatedBy, formatTime(p.CreatedAt))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		printPage(e, len(projects.Items), projects.Total, projects.Page, projects.RowsPerPage, "projects")
+		return nil
+
+	case len(args) == 2 && args[0] == "create":
+		np := struct {
+			Name string `json:"name"`
+		}{
+			Name: args[1],
+		}
+
+		p, data, err := e.client.createProject(ctx, np)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		fmt.Fprintf(e.out, "project %d created\n", p.ID)
+		return nil
+
+	case len(args) >= 2 && args[0] == "members":
+		return members(ctx, e, args[1:])
+
+	case len(args) == 2 && args[0] == "critical-path":
+		return criticalPath(ctx, e, args[1])
+	}
+
+	return usageError("usage: gotasks projects [list | create <name> | members <id> ... | critical-path <id>]")
+}
+
+func criticalPath(ctx context.Context, e env, arg string) error {
+	projectID, err := parseID(arg)
+	if err != nil {
+		return err
+	}
+
+	tasks, data, err := e.client.criticalPath(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	tw := e.table()
+	fmt.Fprintln(tw, "STEP\tID\tTITLE\tASSIGNED\tSTATUS\tPRIORITY\tDUE")
+	for i, t := range tasks {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\t%s\n", i+1, t.ID, t.Title, formatID(t.AssignedTo), t.Status, t.Priority, formatDue(t.DueAt))
+	}
+	return tw.Flush()
+}
+
+func members(ctx context.Context, e env, args []string) error {
+	const usage = "usage: gotasks projects members <id> [list | add <user> <role> | remove <user>]"
+
+	projectID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	args = args[1:]
+
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "list"):
+		members, data, err := e.client.queryMembers(ctx, projectID)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		tw := e.table()
+		fmt.Fprintln(tw, "USER\tROLE\tSINCE")
+		for _, m := range members {
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", m.UserID, m.Role, formatTime(m.CreatedAt))
+		}
+		return tw.Flush()
+
+	case len(args) == 3 && args[0] == "add":
+		userID, err := parseID(args[1])
+		if err != nil {
+			return err
+		}
+
+		nm := struct {
+			UserID int    `json:"user_id"`
+			Role   string `json:"role"`
+		}{
+			UserID: userID,
+			Role:   args[2],
+		}
+
+		_, data, err := e.client.addMember(ctx, projectID, nm)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		fmt.Fprintf(e.out, "user %d added to project %d as %s\n", userID, projectID, args[2])
+		return nil
+
+	case len(args) == 2 && args[0] == "remove":
+		userID, err := parseID(args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := e.client.removeMember(ctx, projectID, userID); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.out, "user %d removed from project %d\n", userID, projectID)
+		return nil
+	}
+
+	return usageError(usage)
+}
+
+func users(ctx context.Context, e env, args []string) error {
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "list"):
+		users, data, err := e.client.queryUsers(ctx)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		tw := e.table()
+		fmt.Fprintln(tw, "ID\tNAME\tEMAIL\tROLE\tACTIVE\tCREATED")
+		for _, u := range users.Items {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%t\t%s\n", u.ID, u.Name, u.Email, u.Role, u.Active, formatTime(u.CreatedAt))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		printPage(e, len(users.Items), users.Total, users.Page, users.RowsPerPage, "users")
+		return nil
+
+	case len(args) == 4 && args[0] == "create":
+		nu := struct {
+			Name     string `json:"name"`
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		}{
+			Name:     args[1],
+			Email:    args[2],
+			Password: args[3],
+		}
+
+		u, data, err := e.client.createUser(ctx, nu)
+		if err != nil {
+			return err
+		}
+
+		if e.output == "json" {
+			return e.printJSON(data)
+		}
+
+		fmt.Fprintf(e.out, "user %d created\n", u.ID)
+		return nil
+	}
+
+	return usageError("usage: gotasks users [list | create <name> <email> <password>]")
+}
+
+// =============================================================================
+
+func login(ctx context.Context, e env, args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: gotasks login <email> <password>")
+	}
+
+	tkn, data, err := e.client.login(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	cfg := e.cfg
+	cfg.Token = tkn.AccessToken
+	cfg.RefreshToken = tkn.RefreshToken
+	if err := saveConfig(e.configPath, cfg); err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(data)
+	}
+
+	fmt.Fprintf(e.out, "logged in as %s\n", args[0])
+	return nil
+}
+
+func logout(ctx context.Context, e env, args []string) error {
+	if len(args) != 0 {
+		return usageError("usage: gotasks logout")
+	}
+
+	if e.cfg.RefreshToken != "" {
+		if err := e.client.revoke(ctx, e.cfg.RefreshToken); err != nil {
+			return err
+		}
+	}
+
+	cfg := e.cfg
+	cfg.Token = ""
+	cfg.RefreshToken = ""
+	if err := saveConfig(e.configPath, cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.out, "logged out")
+	return nil
+}
+
+// =============================================================================
+
+func configure(ctx context.Context, e env, args []string) error {
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "show"):
+		if e.output == "json" {
+			data, err := json.Marshal(e.cfg)
+			if err != nil {
+				return err
+			}
+			return e.printJSON(data)
+		}
+
+		tw := e.table()
+		fmt.Fprintf(tw, "File:\t%s\n", e.configPath)
+		fmt.Fprintf(tw, "URL:\t%s\n", e.cfg.BaseURL)
+		fmt.Fprintf(tw, "Token:\t%s\n", formatSet(e.cfg.Token != ""))
+		fmt.Fprintf(tw, "Refresh token:\t%s\n", formatSet(e.cfg.RefreshToken != ""))
+		return tw.Flush()
+
+	case len(args) == 3 && args[0] == "set":
+		cfg := e.cfg
+
+		switch args[1] {
+		case "url":
+			cfg.BaseURL = args[2]
+
+		case "token":
+			cfg.Token = args[2]
+			cfg.RefreshToken = ""
+
+		default:
+			return usageError("unknown setting %q", args[1])
+		}
+
+		return saveConfig(e.configPath, cfg)
+	}
+
+	return usageError("usage: gotasks config [show | set url <url> | set token <token>]")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// config holds the settings kept between invocations.
+type config struct {
+	BaseURL string `json:"base_url"`
+	Token   string `json:"token,omitempty"`
+
+	// RefreshToken replaces Token once it expires. Only login sets it.
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+const defaultBaseURL = "http://localhost:8080"
+
+// defaultConfigPath returns the location of the config file inside the user
+// configuration directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".gotasks.json"
+	}
+
+	return filepath.Join(dir, "gotasks", "config.json")
+}
+
+// loadConfig reads the config file. A missing file yields the defaults.
+func loadConfig(path string) (config, error) {
+	cfg := config{BaseURL: defaultBaseURL}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return config{}, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("decoding config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// saveConfig writes the config file, creating its directory when needed.
+func saveConfig(path string, cfg config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// This program is a command line client for the task service API.
+package main
+
+import (
+	"TODO-list/app/sdk/errs"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: gotasks [flags] <command> [args]
+
+Tasks:
+  list [-project <id>] [-assigned <user>] [-finished true|false]
+       [-status <status>] [-priority <priority>] [-title <text>]
+       [-due overdue|today|week] [-tz <zone>]
+       [-order <fields>] [-page <n>] [-rows <n>]
+                                                        list the tasks
+  show <id>                                             show a task
+  create -project <id> [-assign <user>] <title> [desc]  create a task
+         [-parent <id>] [-priority low|medium|high|urgent] [-due <time>]
+  update [-priority <priority>] [-due <time>|none] [-parent <id>|none]
+         <id> <title> <description>                     edit a task
+  subtasks [-page <n>] [-rows <n>] <id>                 list the subtasks of a task
+  finish [-subtasks] <id>                               mark a task, and optionally its
+                                                        open subtasks, as finished
+  reopen <id> <reason>                                  reopen a finished task
+  move <id> <status>                                    move a task to todo, in_progress,
+                                                        in_review, done, blocked or cancelled
+  history <id>                                          list the status changes of a task
+  deps <id> [list | add <blocker> | remove <blocker>]   list or change the tasks blocking a task
+  delete <id>                                           delete a task
+
+Projects and users:
+  projects [list | create <name>]
+  projects members <id> [list | add <user> <role> | remove <user>]
+  projects critical-path <id>
+  users [list | create <name> <email> <password>]
+
+Session:
+  login <email> <password>                              get and save tokens for the user
+  logout                                                revoke the saved refresh token
+
+Configuration:
+  config [show | set url <url> | set token <token>]
+
+Every command but users create and login needs a token. login saves one
+that is refreshed when it expires; config set token saves one signed by
+the service administrator with: admin gentoken <user id>
+
+Failed API calls exit with the numeric value of the error code in the
+response, for example 6 for not_found and 4 for invalid_argument.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the process exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gotasks", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	configPath := fs.String("config", defaultConfigPath(), "config file holding the API URL and token")
+	baseURL := fs.String("url", "", "task service URL, overrides the config file")
+	output := fs.String("o", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return errs.InvalidArgument.Value()
+	}
+
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "gotasks: unknown output format %q\n", *output)
+		return errs.InvalidArgument.Value()
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, "gotasks:", err)
+		return errs.Internal.Value()
+	}
+	if *baseURL != "" {
+		cfg.BaseURL = *baseURL
+	}
+
+	commands := map[string]func(context.Context, env, []string) error{
+		"list":     listTasks,
+		"show":     showTask,
+		"create":   createTask,
+		"update":   updateTask,
+		"finish":   finishTask,
+		"subtasks": listSubtasks,
+		"reopen":   reopenTask,
+		"move":     moveTask,
+		"history":  taskHistory,
+		"deps":     dependencies,
+		"delete":   deleteTask,
+		"projects": projects,
+		"users":    users,
+		"config":   configure,
+		"login":    login,
+		"logout":   logout,
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errs.InvalidArgument.Value()
+	}
+
+	cmd, exists := commands[fs.Arg(0)]
+	if !exists {
+		fmt.Fprintf(stderr, "gotasks: unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return errs.InvalidArgument.Value()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := env{
+		out:        stdout,
+		output:     *output,
+		client:     newClient(cfg.BaseURL, cfg.Token, cfg.RefreshToken),
+		cfg:        cfg,
+		configPath: *configPath,
+	}
+	e.client.onRefresh = func(token string, refreshToken string) error {
+		cfg.Token = token
+		cfg.RefreshToken = refreshToken
+		return saveConfig(*configPath, cfg)
+	}
+
+	if err := cmd(ctx, e, fs.Args()[1:]); err != nil {
+		fmt.Fprintln(stderr, "gotasks:", err)
+
+		var errsErr *errs.Error
+		if errors.As(err, &errsErr) {
+			return errsErr.Code.Value()
+		}
+		return errs.Internal.Value()
+	}
+
+	return errs.OK.Value()
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"TODO-list/app/sdk/auth"
+	"TODO-list/app/sdk/errs"
+	"TODO-list/app/sdk/mux"
+	"TODO-list/business/domain/userbus"
+	"TODO-list/business/sdk/memdb"
+	"TODO-list/foundation/keystore"
+	"TODO-list/foundation/logger"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGotasks(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", nil)
+	ks := keystore.New()
+	privatePEM, err := keystore.GenerateRSAKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ks.Add("test", privatePEM))
+
+	ath, err := auth.New(auth.Config{KeyLookup: ks, Issuer: "tasks"})
+	require.NoError(t, err)
+
+	api, err := mux.WebAPI(mux.Config{Log: log, MemDB: memdb.New(), Auth: ath, AuthKID: "test"})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	exec := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		args = append([]string{"-config", configPath}, args...)
+		code := run(args, &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, _, _ := exec("config", "set", "url", srv.URL)
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("create", "-project", "1", "Task")
+	assert.Equal(t, errs.Unauthenticated.Value(), code, "creating without a token must fail")
+
+	code, out, _ := exec("users", "create", "Ana", "ana@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "user 1 created\n", out)
+
+	code, _, _ = exec("users", "create", "Eve", "eve@example.com", strings.Repeat("x", 73))
+	assert.Equal(t, errs.InvalidArgument.Value(), code, "bcrypt reads at most 72 bytes")
+
+	code, _, _ = exec("login", "ana@example.com", "wrong-pass")
+	assert.Equal(t, errs.Unauthenticated.Value(), code)
+
+	code, out, _ = exec("login", "ana@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "logged in as ana@example.com\n", out)
+
+	code, _, _ = exec("projects", "create", "Home")
+	require.Equal(t, 0, code)
+
+	due := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	code, out, _ = exec("create", "-project", "1", "-assign", "1", "-priority", "high", "-due", due.Format(time.RFC3339), "Buy milk", "Two liters")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 1 created\n", out)
+
+	code, _, _ = exec("create", "-project", "1", "-priority", "someday", "Buy bread")
+	assert.Equal(t, errs.InvalidArgument.Value(), code)
+
+	code, _, _ = exec("create", "-project", "1", "-due", time.Now().Add(-time.Hour).Format(time.RFC3339), "Buy bread")
+	assert.Equal(t, errs.InvalidArgument.Value(), code, "a new task can not be due in the past")
+
+	code, _, _ = exec("list", "-due", "overdue", "-tz", "Mars/Olympus")
+	assert.Equal(t, errs.InvalidArgument.Value(), code)
+
+	code, _, _ = exec("update", "1", "Buy oat milk", "One liter")
+	require.Equal(t, 0, code)
+
+	code, out, _ = exec("move", "1", "in_progress")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 1 is in_progress\n", out)
+
+	code, _, _ = exec("move", "1", "paused")
+	assert.Equal(t, errs.InvalidArgument.Value(), code)
+
+	code, _, _ = exec("finish", "1")
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("move", "1", "in_progress")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code, "done is final")
+
+	code, _, _ = exec("reopen", "1", "")
+	assert.Equal(t, errs.InvalidArgument.Value(), code, "a reason is required")
+
+	code, out, _ = exec("reopen", "1", "forgot the receipt")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 1 reopened, 1 time(s) so far\n", out)
+
+	code, _, _ = exec("finish", "1")
+	require.Equal(t, 0, code)
+
+	code, out, _ = exec("history", "1")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "in_progress")
+	assert.Contains(t, out, "forgot the receipt")
+
+	code, out, _ = exec("-o", "json", "show", "1")
+	require.Equal(t, 0, code)
+
+	var task task
+	require.NoError(t, json.Unmarshal([]byte(out), &task))
+	assert.Equal(t, "Buy oat milk", task.Title)
+	assert.Equal(t, 1, task.AssignedTo)
+	assert.False(t, task.FinishedAt.IsZero())
+	assert.Equal(t, "done", task.Status)
+	assert.Equal(t, 1, task.ReopenCount)
+	assert.Equal(t, "high", task.Priority, "update keeps the priority")
+	if assert.NotNil(t, task.DueAt, "update keeps the due date") {
+		assert.True(t, due.Equal(*task.DueAt))
+	}
+	assert.Zero(t, task.LateSeconds)
+
+	code, out, _ = exec("list")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Buy oat milk")
+
+	code, out, _ = exec("create", "-project", "1", "Groceries")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 2 created\n", out)
+
+	code, out, _ = exec("create", "-project", "1", "-parent", "2", "Buy eggs")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 3 created\n", out)
+
+	code, _, _ = exec("update", "-parent", "3", "2", "Groceries", "")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code, "a task can not move under its subtask")
+
+	code, _, _ = exec("finish", "2")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code, "the subtask is open")
+
+	code, out, _ = exec("subtasks", "2")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Buy eggs")
+
+	code, _, _ = exec("finish", "-subtasks", "2")
+	require.Equal(t, 0, code)
+
+	code, out, _ = exec("-o", "json", "show", "2")
+	require.Equal(t, 0, code)
+
+	var parent struct {
+		Status   string    `json:"status"`
+		Progress *progress `json:"progress"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parent))
+	assert.Equal(t, "done", parent.Status)
+	assert.Equal(t, &progress{Done: 1, Total: 1, Percent: 100}, parent.Progress)
+
+	code, _, _ = exec("create", "-project", "1", "Cook dinner")
+	require.Equal(t, 0, code)
+	code, _, _ = exec("create", "-project", "1", "Wash dishes")
+	require.Equal(t, 0, code)
+
+	code, out, _ = exec("deps", "5", "add", "4")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 5 blocked by task 4\n", out)
+
+	code, _, _ = exec("deps", "5", "add", "4")
+	assert.Equal(t, errs.AlreadyExists.Value(), code)
+
+	code, _, _ = exec("deps", "4", "add", "5")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code, "the link would make a cycle")
+
+	code, _, _ = exec("finish", "5")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code, "the blocker is open")
+
+	code, out, _ = exec("deps", "5")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Cook dinner")
+
+	code, out, _ = exec("projects", "critical-path", "1")
+	require.Equal(t, 0, code)
+	assert.Regexp(t, "(?s)1 +4 +Cook dinner.*2 +5 +Wash dishes", out)
+
+	code, out, _ = exec("deps", "5", "remove", "4")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "task 5 no longer blocked by task 4\n", out)
+
+	code, _, _ = exec("finish", "5")
+	require.Equal(t, 0, code)
+
+	code, _, stderr := exec("create", "-project", "9", "Orphan")
+	assert.Equal(t, errs.NotFound.Value(), code)
+	assert.Contains(t, stderr, "project not found")
+
+	code, _, stderr = exec("show", "999")
+	assert.Equal(t, errs.NotFound.Value(), code)
+	assert.Contains(t, stderr, "task not found")
+
+	code, _, _ = exec("show", "abc")
+	assert.Equal(t, errs.InvalidArgument.Value(), code)
+
+	code, _, _ = exec("-url", "http://127.0.0.1:1", "list")
+	assert.Equal(t, errs.Unavailable.Value(), code)
+
+	// A refused access token is refreshed and saved.
+	cfg, err := loadConfig(configPath)
+	require.NoError(t, err)
+	cfg.Token = "expired"
+	require.NoError(t, saveConfig(configPath, cfg))
+
+	code, _, _ = exec("list")
+	require.Equal(t, 0, code)
+
+	cfg, err = loadConfig(configPath)
+	require.NoError(t, err)
+	assert.NotEqual(t, "expired", cfg.Token)
+
+	code, out, _ = exec("logout")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "logged out\n", out)
+
+	code, _, _ = exec("list")
+	assert.Equal(t, errs.Unauthenticated.Value(), code)
+
+	// Tokens signed by the administrator work without a refresh token.
+	token, err := ath.GenerateToken("test", ath.NewClaims(userbus.User{ID: 1, Role: userbus.RoleUser}, time.Hour))
+	require.NoError(t, err)
+
+	code, _, _ = exec("config", "set", "token", token)
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("list")
+	assert.Equal(t, 0, code)
+
+	code, _, _ = exec("users", "list")
+	assert.Equal(t, errs.PermissionDenied.Value(), code, "only admins list the users")
+
+	// Only project members can be assigned to its tasks.
+	code, _, _ = exec("users", "create", "Bob", "bob@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("create", "-project", "1", "-assign", "2", "Walk dog")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code)
+
+	code, out, _ = exec("projects", "members", "1", "add", "2", "viewer")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "user 2 added to project 1 as viewer\n", out)
+
+	code, _, _ = exec("projects", "members", "1", "add", "2", "viewer")
+	assert.Equal(t, errs.AlreadyExists.Value(), code)
+
+	code, out, _ = exec("projects", "members", "1")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "owner")
+	assert.Contains(t, out, "viewer")
+
+	code, _, _ = exec("create", "-project", "1", "-assign", "2", "Walk dog")
+	assert.Equal(t, 0, code)
+
+	code, _, _ = exec("projects", "members", "1", "remove", "1")
+	assert.Equal(t, errs.FailedPrecondition.Value(), code, "the last owner stays")
+
+	// Maintainers manage the members but can not grant or take away the
+	// owner role.
+	code, _, _ = exec("users", "create", "Cid", "cid@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+	code, _, _ = exec("users", "create", "Dan", "dan@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("projects", "members", "1", "add", "3", "maintainer")
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("login", "cid@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("projects", "members", "1", "add", "4", "owner")
+	assert.Equal(t, errs.PermissionDenied.Value(), code, "a maintainer can not add an owner")
+
+	code, _, _ = exec("projects", "members", "1", "remove", "1")
+	assert.Equal(t, errs.PermissionDenied.Value(), code, "a maintainer can not remove an owner")
+
+	code, _, _ = exec("projects", "members", "1", "add", "4", "member")
+	assert.Equal(t, 0, code)
+
+	// Other users can read the task but not finish or edit it.
+
+	code, _, _ = exec("login", "bob@example.com", "secret-pass")
+	require.Equal(t, 0, code)
+
+	code, _, _ = exec("show", "1")
+	assert.Equal(t, 0, code)
+
+	code, _, _ = exec("finish", "1")
+	assert.Equal(t, errs.PermissionDenied.Value(), code)
+
+	code, _, _ = exec("update", "1", "Buy rice", "")
+	assert.Equal(t, errs.PermissionDenied.Value(), code)
+
+	// Viewers can not manage the members.
+	code, _, _ = exec("projects", "members", "1", "remove", "2")
+	assert.Equal(t, errs.PermissionDenied.Value(), code)
+
+	// Deactivating a user shuts out their access token right away and
+	// revokes their refresh tokens, so the next refresh asks them to log in
+	// again.
+	admin, err := ath.GenerateToken("test", ath.NewClaims(userbus.User{ID: 1, Role: userbus.RoleAdmin}, time.Hour))
+	require.NoError(t, err)
+
+	_, err = newClient(srv.URL, admin, "").do(context.Background(), http.MethodDelete, "/api/users/2", nil)
+	require.NoError(t, err)
+
+	cfg, err = loadConfig(configPath)
+	require.NoError(t, err)
+
+	_, err = newClient(srv.URL, cfg.Token, "").do(context.Background(), http.MethodGet, "/api/tasks", nil)
+	var errsErr *errs.Error
+	if assert.ErrorAs(t, err, &errsErr) {
+		assert.Equal(t, errs.Unauthenticated, errsErr.Code)
+	}
+
+	cfg.Token = "expired"
+	require.NoError(t, saveConfig(configPath, cfg))
+
+	code, _, _ = exec("list")
+	assert.Equal(t, errs.Unauthenticated.Value(), code)
+}
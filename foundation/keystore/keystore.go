@@ -0,0 +1,175 @@
+// Package keystore implements the auth.KeyLookup interface. It loads RSA
+// private keys from PEM files where the file name, without the .pem
+// extension, is the key id used in the kid header of the tokens.
+package keystore
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrKeyNotFound is returned when no key is stored under the key id.
+var ErrKeyNotFound = errors.New("key not found")
+
+type key struct {
+	privatePEM string
+	publicPEM  string
+}
+
+// KeyStore represents an in memory store implementation of the auth.KeyLookup
+// interface.
+type KeyStore struct {
+	store map[string]key
+}
+
+// New constructs an empty KeyStore ready for use.
+func New() *KeyStore {
+	return &KeyStore{
+		store: map[string]key{},
+	}
+}
+
+// LoadRSAKeys loads the set of RSA PEM files from the root of the file
+// system and returns the number of keys loaded.
+func (ks *KeyStore) LoadRSAKeys(fsys fs.FS) (int, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return 0, fmt.Errorf("reading directory: %w", err)
+	}
+
+	var n int
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+
+		f, err := fsys.Open(entry.Name())
+		if err != nil {
+			return 0, fmt.Errorf("opening key file: %w", err)
+		}
+
+		// Limit PEM file size to 1 megabyte. This should be reasonable for
+		// almost any PEM file and prevents shenanigans like linking the file
+		// to /dev/random or something like that.
+		privatePEM, err := io.ReadAll(io.LimitReader(f, 1024*1024))
+		f.Close()
+		if err != nil {
+			return 0, fmt.Errorf("reading auth private key: %w", err)
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+		if err := ks.Add(kid, string(privatePEM)); err != nil {
+			return 0, fmt.Errorf("key %s: %w", entry.Name(), err)
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// Add stores the RSA private key in PEM format under the key id.
+func (ks *KeyStore) Add(kid string, privatePEM string) error {
+	privateKey, err := ParsePrivateKey(privatePEM)
+	if err != nil {
+		return err
+	}
+
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return fmt.Errorf("marshaling public key: %w", err)
+	}
+
+	publicBlock := pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: asn1Bytes,
+	}
+
+	ks.store[kid] = key{
+		privatePEM: privatePEM,
+		publicPEM:  string(pem.EncodeToMemory(&publicBlock)),
+	}
+
+	return nil
+}
+
+// KIDs returns the key ids in the store in sorted order.
+func (ks *KeyStore) KIDs() []string {
+	kids := make([]string, 0, len(ks.store))
+	for kid := range ks.store {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	return kids
+}
+
+// PrivateKey searches the key store for a given kid and returns the private
+// key in PEM format.
+func (ks *KeyStore) PrivateKey(kid string) (string, error) {
+	key, found := ks.store[kid]
+	if !found {
+		return "", fmt.Errorf("kid[%s]: %w", kid, ErrKeyNotFound)
+	}
+
+	return key.privatePEM, nil
+}
+
+// PublicKey searches the key store for a given kid and returns the public
+// key in PEM format.
+func (ks *KeyStore) PublicKey(kid string) (string, error) {
+	key, found := ks.store[kid]
+	if !found {
+		return "", fmt.Errorf("kid[%s]: %w", kid, ErrKeyNotFound)
+	}
+
+	return key.publicPEM, nil
+}
+
+// ParsePrivateKey decodes an RSA private key stored in PKCS #1 or PKCS #8 PEM
+// format.
+func ParsePrivateKey(privatePEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, errors.New("invalid key: key must be PEM encoded")
+	}
+
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid key: not an RSA private key")
+	}
+
+	return privateKey, nil
+}
+
+// GenerateRSAKey returns a new 2048 bit RSA private key in PKCS #1 PEM
+// format.
+func GenerateRSAKey(random io.Reader) (string, error) {
+	privateKey, err := rsa.GenerateKey(random, 2048)
+	if err != nil {
+		return "", fmt.Errorf("generating key: %w", err)
+	}
+
+	privateBlock := pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}
+
+	return string(pem.EncodeToMemory(&privateBlock)), nil
+}
@@ -20,6 +20,7 @@ type TraceIDFn func(ctx context.Context) string
 // Logger represents a logger for logging information.
 type Logger struct {
 	handler   slog.Handler
+	level     *slog.LevelVar
 	traceIDFn TraceIDFn
 }
 
@@ -34,9 +35,21 @@ func NewWithEvents(w io.Writer, minLevel Level, serviceName string, traceIDFn Tr
 }
 
 // NewWithHandler returns a new log for application use with the underlying
-// handler.
+// handler. The log starts at the lowest level the handler accepts and checks
+// its own level before the handler's, so SetLevel can raise the level and
+// lower it back, but never below what the handler accepts.
 func NewWithHandler(h slog.Handler) *Logger {
-	return &Logger{handler: h}
+	level := &slog.LevelVar{}
+	level.Set(slog.LevelError)
+
+	for _, l := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn} {
+		if h.Enabled(context.Background(), l) {
+			level.Set(l)
+			break
+		}
+	}
+
+	return &Logger{handler: h, level: level}
 }
 
 // NewStdLogger returns a standard library Logger that wraps the slog Logger.
@@ -84,10 +97,20 @@ func (log *Logger) Errorc(ctx context.Context, caller int, msg string, args ...a
 	log.write(ctx, LevelError, caller, msg, args...)
 }
 
+// Level returns the minimum level the log writes.
+func (log *Logger) Level() Level {
+	return Level(log.level.Level())
+}
+
+// SetLevel changes the minimum level the log writes while the program runs.
+func (log *Logger) SetLevel(level Level) {
+	log.level.Set(slog.Level(level))
+}
+
 func (log *Logger) write(ctx context.Context, level Level, caller int, msg string, args ...any) {
 	slogLevel := slog.Level(level)
 
-	if !log.handler.Enabled(ctx, slogLevel) {
+	if slogLevel < log.level.Level() || !log.handler.Enabled(ctx, slogLevel) {
 		return
 	}
 
@@ -119,8 +142,12 @@ func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, e
 		return a
 	}
 
+	// The level is kept in a variable so it can be changed at runtime.
+	level := &slog.LevelVar{}
+	level.Set(slog.Level(minLevel))
+
 	// Construct the slog JSON handler for use.
-	handler := slog.Handler(slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: slog.Level(minLevel), ReplaceAttr: f}))
+	handler := slog.Handler(slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: level, ReplaceAttr: f}))
 
 	// If events are to be processed, wrap the JSON handler around the custom
 	// log handler.
@@ -138,6 +165,7 @@ func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, e
 
 	return &Logger{
 		handler:   handler,
+		level:     level,
 		traceIDFn: traceIDFn,
 	}
 }
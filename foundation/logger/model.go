@@ -18,6 +18,21 @@ const (
 	LevelError = Level(slog.LevelError)
 )
 
+// String returns the name of the level.
+func (l Level) String() string {
+	return slog.Level(l).String()
+}
+
+// ParseLevel converts a level name such as "debug" or "INFO" to a Level.
+func ParseLevel(name string) (Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, err
+	}
+
+	return Level(level), nil
+}
+
 // Record represents the data that is being logged.
 type Record struct {
 	Time       time.Time
@@ -0,0 +1,361 @@
+// Package metrics provides counters, gauges and histograms that are exposed
+// in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default histogram buckets, in seconds, tailored to
+// measure the latency of requests and database calls.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry used by the package level constructors.
+var Default = New()
+
+// collector is implemented by every metric kept in a registry.
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds a set of metrics.
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+// New constructs an empty registry.
+func New() *Registry {
+	return &Registry{
+		collectors: make(map[string]collector),
+	}
+}
+
+// register adds the collector to the registry. Registering two metrics with
+// the same name is a programming error.
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.collectors[c.name()]; exists {
+		panic(fmt.Sprintf("metrics: %q registered twice", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// WriteTo writes every metric of the registry, sorted by name, in the
+// Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].name() < collectors[j].name()
+	})
+
+	cw := countWriter{w: w}
+	bw := bufio.NewWriter(&cw)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler returns an http handler serving the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
+
+// =============================================================================
+
+// NewCounter constructs a counter registered with the Default registry.
+func NewCounter(name string, help string, labelNames ...string) *Counter {
+	return Default.NewCounter(name, help, labelNames...)
+}
+
+// NewHistogram constructs a histogram registered with the Default registry.
+func NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labelNames...)
+}
+
+// NewGaugeFunc registers a gauge whose value is read from fn with the Default
+// registry.
+func NewGaugeFunc(name string, help string, fn func() float64) {
+	Default.NewGaugeFunc(name, help, fn)
+}
+
+// NewCounterFunc registers a counter whose value is read from fn with the
+// Default registry.
+func NewCounterFunc(name string, help string, fn func() float64) {
+	Default.NewCounterFunc(name, help, fn)
+}
+
+// =============================================================================
+
+// Counter is a metric that only goes up, partitioned by its labels.
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter constructs a counter registered with the registry.
+func (r *Registry) NewCounter(name string, help string, labelNames ...string) *Counter {
+	c := Counter{
+		desc:   desc{metric: name, help: help, labelNames: labelNames},
+		values: make(map[string]float64),
+	}
+	r.register(&c)
+
+	return &c
+}
+
+// Inc increments the counter for the label values by one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter for the label values by v.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] += v
+}
+
+// Value returns the current value of the counter for the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[key]
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.header(w, "counter")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.labelNames) == 0 && len(c.values) == 0 {
+		writeSample(w, c.metric, "", 0)
+		return
+	}
+
+	for _, key := range sortedKeys(c.values) {
+		writeSample(w, c.metric, key, c.values[key])
+	}
+}
+
+// =============================================================================
+
+// Histogram samples observations and counts them in buckets, partitioned by
+// its labels.
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram constructs a histogram registered with the registry. The
+// buckets are the upper bounds, in increasing order, without +Inf.
+func (r *Registry) NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+	h := Histogram{
+		desc:    desc{metric: name, help: help, labelNames: labelNames},
+		buckets: buckets,
+		values:  make(map[string]*histogramValue),
+	}
+	r.register(&h)
+
+	return &h
+}
+
+// Observe adds a single observation for the label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hv, exists := h.values[key]
+	if !exists {
+		hv = &histogramValue{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hv
+	}
+
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hv.counts[i]++
+		}
+	}
+	hv.sum += v
+	hv.count++
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.header(w, "histogram")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, key := range sortedKeys(h.values) {
+		hv := h.values[key]
+
+		for i, upper := range h.buckets {
+			writeSample(w, h.metric+"_bucket", joinLabels(key, "le", formatFloat(upper)), float64(hv.counts[i]))
+		}
+		writeSample(w, h.metric+"_bucket", joinLabels(key, "le", "+Inf"), float64(hv.count))
+		writeSample(w, h.metric+"_sum", key, hv.sum)
+		writeSample(w, h.metric+"_count", key, float64(hv.count))
+	}
+}
+
+// =============================================================================
+
+// funcMetric reads its single value from a function when written.
+type funcMetric struct {
+	desc
+	kind string
+	fn   func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from fn.
+func (r *Registry) NewGaugeFunc(name string, help string, fn func() float64) {
+	r.register(&funcMetric{desc: desc{metric: name, help: help}, kind: "gauge", fn: fn})
+}
+
+// NewCounterFunc registers a counter whose value is read from fn.
+func (r *Registry) NewCounterFunc(name string, help string, fn func() float64) {
+	r.register(&funcMetric{desc: desc{metric: name, help: help}, kind: "counter", fn: fn})
+}
+
+func (f *funcMetric) write(w *bufio.Writer) {
+	f.header(w, f.kind)
+	writeSample(w, f.metric, "", f.fn())
+}
+
+// =============================================================================
+
+// desc holds the parts every metric shares.
+type desc struct {
+	metric     string
+	help       string
+	labelNames []string
+}
+
+func (d *desc) name() string {
+	return d.metric
+}
+
+// key renders the label pairs the way they appear between the braces.
+func (d *desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf("metrics: %q expects %d label values, got %d", d.metric, len(d.labelNames), len(labelValues)))
+	}
+
+	var b strings.Builder
+	for i, name := range d.labelNames {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(labelValues[i]))
+		b.WriteByte('"')
+	}
+
+	return b.String()
+}
+
+func (d *desc) header(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metric, helpEscaper.Replace(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metric, kind)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func joinLabels(key string, name string, value string) string {
+	pair := name + `="` + value + `"`
+	if key == "" {
+		return pair
+	}
+
+	return key + "," + pair
+}
+
+func writeSample(w *bufio.Writer, name string, labels string, v float64) {
+	w.WriteString(name)
+	if labels != "" {
+		w.WriteByte('{')
+		w.WriteString(labels)
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// countWriter counts the bytes written through it.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,62 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"TODO-list/foundation/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTo(t *testing.T) {
+	reg := metrics.New()
+
+	requests := reg.NewCounter("requests_total", "Number of requests.", "route", "code")
+	requests.Inc("GET /tasks", "200")
+	requests.Inc("GET /tasks", "200")
+	requests.Inc(`GET /"quoted"`, "404")
+
+	latency := reg.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	latency.Observe(0.05, "GET /tasks")
+	latency.Observe(0.5, "GET /tasks")
+	latency.Observe(5, "GET /tasks")
+
+	reg.NewGaugeFunc("open_connections", "Open connections.", func() float64 { return 3 })
+	reg.NewCounter("created_total", "Created.")
+
+	var buf bytes.Buffer
+	_, err := reg.WriteTo(&buf)
+	require.NoError(t, err)
+
+	want := `# HELP created_total Created.
+# TYPE created_total counter
+created_total 0
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="GET /tasks",le="0.1"} 1
+latency_seconds_bucket{route="GET /tasks",le="1"} 2
+latency_seconds_bucket{route="GET /tasks",le="+Inf"} 3
+latency_seconds_sum{route="GET /tasks"} 5.55
+latency_seconds_count{route="GET /tasks"} 3
+# HELP open_connections Open connections.
+# TYPE open_connections gauge
+open_connections 3
+# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{route="GET /\"quoted\"",code="404"} 1
+requests_total{route="GET /tasks",code="200"} 2
+`
+	assert.Equal(t, want, buf.String())
+	assert.Equal(t, float64(2), requests.Value("GET /tasks", "200"))
+}
+
+func TestRegisterTwice(t *testing.T) {
+	reg := metrics.New()
+	reg.NewCounter("requests_total", "Number of requests.")
+
+	assert.Panics(t, func() {
+		reg.NewCounter("requests_total", "Number of requests.")
+	})
+}
@@ -2,12 +2,20 @@ package otel
 
 import (
 	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// defaultTracer is used when no tracer was injected into the context, such
+// as in the admin tooling.
+var defaultTracer trace.Tracer = noop.NewTracerProvider().Tracer("")
+
 type ctxKey int
 
 const (
 	traceIDKey ctxKey = iota + 1
+	tracerKey
 )
 
 func setTraceID(ctx context.Context, traceID string) context.Context {
@@ -23,3 +31,16 @@ func GetTraceID(ctx context.Context) string {
 
 	return v
 }
+
+func setTracer(ctx context.Context, tracer trace.Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey, tracer)
+}
+
+func getTracer(ctx context.Context) trace.Tracer {
+	v, ok := ctx.Value(tracerKey).(trace.Tracer)
+	if !ok {
+		return defaultTracer
+	}
+
+	return v
+}
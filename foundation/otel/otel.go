@@ -3,11 +3,153 @@ package otel
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Set of exporters the spans can be sent to.
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// propagator reads and writes the traceparent header of the W3C
+// TraceContext standard.
+var propagator = propagation.TraceContext{}
+
+// Config defines the information needed to init tracing.
+type Config struct {
+	ServiceName string
+	Exporter    string
+	Host        string
+	File        string
+	Probability float64
+}
+
+// InitTracing configures open telemetry to be used with the service. Trace
+// ids are generated even when no exporter is configured so the logs can
+// still be correlated by trace id. The returned function flushes and stops
+// the exporter.
+func InitTracing(cfg Config) (trace.TracerProvider, func(ctx context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+
+	switch cfg.Exporter {
+	case ExporterNone, "":
+
+	case ExporterOTLP:
+		exp, err := otlptracehttp.New(
+			context.Background(),
+			otlptracehttp.WithEndpoint(cfg.Host),
+			otlptracehttp.WithInsecure(),
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating otlp exporter: %w", err)
+		}
+		exporter = exp
+
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening trace file: %w", err)
+		}
+
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("creating file exporter: %w", err)
+		}
+		exporter = fileExporter{SpanExporter: exp, file: f}
+
+	default:
+		return nil, nil, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Probability))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+		)),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	traceProvider := sdktrace.NewTracerProvider(opts...)
+
+	// We must set this provider as the global provider for things to work,
+	// but we pass this provider around the program where needed to collect
+	// our traces.
+	otel.SetTracerProvider(traceProvider)
+
+	// Extract incoming trace contexts and add this information to outgoing
+	// requests using the W3C TraceContext standard.
+	otel.SetTextMapPropagator(propagator)
+
+	return traceProvider, traceProvider.Shutdown, nil
+}
+
+// InjectTracing saves the tracer and the trace id of the span in the context
+// for later use by AddSpan and the logger.
+func InjectTracing(ctx context.Context, tracer trace.Tracer) context.Context {
+	ctx = setTracer(ctx, tracer)
+
+	traceID := trace.SpanFromContext(ctx).SpanContext().TraceID()
+	if traceID.IsValid() {
+		ctx = setTraceID(ctx, traceID.String())
+	}
+
+	return ctx
+}
+
 // InjectTraceID generates a new trace id and stores it in the context.
 func InjectTraceID(ctx context.Context) context.Context {
 	return setTraceID(ctx, uuid.NewString())
 }
+
+// ExtractTraceContext returns a context that carries the remote span found in
+// the traceparent header of the request, if there is one.
+func ExtractTraceContext(ctx context.Context, r *http.Request) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// AddTraceToHeader writes the traceparent of the span in the context to the
+// header.
+func AddTraceToHeader(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// AddSpan adds an otel span to the existing trace. The span must be ended by
+// the caller.
+func AddSpan(ctx context.Context, spanName string, keyValues ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := getTracer(ctx).Start(ctx, spanName)
+	span.SetAttributes(keyValues...)
+
+	return ctx, span
+}
+
+// =============================================================================
+
+// fileExporter closes the file once the spans have been flushed.
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
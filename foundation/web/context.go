@@ -9,6 +9,7 @@ type ctxKey int
 
 const (
 	writerKey ctxKey = iota + 1
+	routeKey
 )
 
 func setWriter(ctx context.Context, w http.ResponseWriter) context.Context {
@@ -24,3 +25,18 @@ func GetWriter(ctx context.Context) http.ResponseWriter {
 
 	return v
 }
+
+func setRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+// GetRoute returns the pattern the request was routed by, such as
+// "GET /api/tasks/{id}", which keeps ids out of metric labels.
+func GetRoute(ctx context.Context) string {
+	v, ok := ctx.Value(routeKey).(string)
+	if !ok {
+		return ""
+	}
+
+	return v
+}
@@ -36,11 +36,9 @@ type App struct {
 
 // NewApp creates an App value that handle a set of routes for the application.
 func NewApp(log Logger, mw ...MidFunc) *App {
-	// Create an OpenTelemetry HTTP Handler which wraps our router. This will start
-	// the initial span and annotate it with information about the request/trusted.
-	//
-	// This is configured to use the W3C TraceContext standard to set the remote
-	// parent if a client request includes the appropriate headers.
+	// Tracing is provided by the middleware passed in. It uses the W3C
+	// TraceContext standard to set the remote parent if a client request
+	// includes the appropriate headers.
 	// https://w3c.github.io/trace-context/
 
 	mux := http.NewServeMux()
@@ -93,8 +91,15 @@ func (a *App) corsHandler(webHandler HandlerFunc) HandlerFunc {
 // pair to the application server mux. Does not include the application
 // middleware or OTEL tracing.
 func (a *App) HandlerFuncNoMid(method string, group string, path string, handlerFunc HandlerFunc) {
+	finalPath := path
+	if group != "" {
+		finalPath = "/" + group + path
+	}
+	finalPath = fmt.Sprintf("%s %s", method, finalPath)
+
 	h := func(w http.ResponseWriter, r *http.Request) {
 		ctx := setWriter(r.Context(), w)
+		ctx = setRoute(ctx, finalPath)
 
 		resp := handlerFunc(ctx, r)
 
@@ -103,12 +108,6 @@ func (a *App) HandlerFuncNoMid(method string, group string, path string, handler
 		}
 	}
 
-	finalPath := path
-	if group != "" {
-		finalPath = "/" + group + path
-	}
-	finalPath = fmt.Sprintf("%s %s", method, finalPath)
-
 	a.mux.HandleFunc(finalPath, h)
 }
 
@@ -122,8 +121,15 @@ func (a *App) HandlerFunc(method string, group string, path string, handlerFunc
 		handlerFunc = wrapMiddleware([]MidFunc{a.corsHandler}, handlerFunc)
 	}
 
+	finalPath := path
+	if group != "" {
+		finalPath = "/" + group + path
+	}
+	finalPath = fmt.Sprintf("%s %s", method, finalPath)
+
 	h := func(w http.ResponseWriter, r *http.Request) {
 		ctx := setWriter(r.Context(), w)
+		ctx = setRoute(ctx, finalPath)
 
 		resp := handlerFunc(ctx, r)
 
@@ -132,12 +138,6 @@ func (a *App) HandlerFunc(method string, group string, path string, handlerFunc
 		}
 	}
 
-	finalPath := path
-	if group != "" {
-		finalPath = "/" + group + path
-	}
-	finalPath = fmt.Sprintf("%s %s", method, finalPath)
-
 	a.mux.HandleFunc(finalPath, h)
 }
 
@@ -156,18 +156,19 @@ func (a *App) RawHandlerFunc(method string, group string, path string, rawHandle
 		handlerFunc = wrapMiddleware([]MidFunc{a.corsHandler}, handlerFunc)
 	}
 
-	h := func(w http.ResponseWriter, r *http.Request) {
-		ctx := setWriter(r.Context(), w)
-
-		handlerFunc(ctx, r)
-	}
-
 	finalPath := path
 	if group != "" {
 		finalPath = "/" + group + path
 	}
 	finalPath = fmt.Sprintf("%s %s", method, finalPath)
 
+	h := func(w http.ResponseWriter, r *http.Request) {
+		ctx := setWriter(r.Context(), w)
+		ctx = setRoute(ctx, finalPath)
+
+		handlerFunc(ctx, r)
+	}
+
 	a.mux.HandleFunc(finalPath, h)
 }
 
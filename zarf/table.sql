@@ -1,26 +0,0 @@
-CREATE TABLE task (
-    id int AUTO_INCREMENT primary key,
-    title varchar(255),
-    description text,
-    created_at DATETIME null,
-    finished_at DATETIME null,
-    created_by INT NOT NULL,
-    assigned_to INT NULL
-);
-
-CREATE TABLE users (
-    id INT AUTO_INCREMENT PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    email VARCHAR(255) NOT NULL UNIQUE,
-    status BOOLEAN NOT NULL DEFAULT TRUE, 
-    created_at NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at NOT NULL DEFAULT CURRENT_TIMESTAMP,
-);
-
-CREATE TABLE project (
-    id INT AUTO_INCREMENT PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    active BOOLEAN NOT NULL DEFAULT TRUE,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    created_by INT NOT NULL
-);